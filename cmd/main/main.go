@@ -10,8 +10,10 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/UnknownOlympus/hephaestus/internal/buildinfo"
 	"github.com/UnknownOlympus/hephaestus/internal/client/hermes"
 	"github.com/UnknownOlympus/hephaestus/internal/config"
+	"github.com/UnknownOlympus/hephaestus/internal/lib/outage"
 	"github.com/UnknownOlympus/hephaestus/internal/metrics"
 	"github.com/UnknownOlympus/hephaestus/internal/repository"
 	"github.com/UnknownOlympus/hephaestus/internal/server"
@@ -39,6 +41,9 @@ func main() {
 	cfg := config.MustLoad()
 
 	logger := setupLogger(cfg.Env)
+	build := buildinfo.Get()
+	logger.InfoContext(ctx, "Starting Hephaestus",
+		"version", build.Version, "commit", build.Commit, "build_date", build.BuildDate)
 
 	// Create a separate registry for metrics with exemplar
 	reg := prometheus.NewRegistry()
@@ -47,30 +52,109 @@ func main() {
 	appMetrics := metrics.NewMetrics(reg)
 
 	dtb, err := repository.NewDatabase(
-		cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.User, cfg.Postgres.Password, cfg.Postgres.Dbname)
+		cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.User, cfg.Postgres.Password, cfg.Postgres.Dbname,
+		repository.PoolOptions{
+			MaxConns:        cfg.Postgres.MaxConns,
+			MinConns:        cfg.Postgres.MinConns,
+			MaxConnIdleTime: cfg.Postgres.MaxConnIdleTime,
+		})
 	if err != nil {
 		log.Fatalf("Failed to connect to DB: %v", err)
 	}
 
-	hermesClient, hermesConn, err := hermes.NewClient(cfg.HermesAddr)
+	hermesOpts := []hermes.Option{
+		hermes.WithLogger(logger),
+		hermes.WithMetrics(appMetrics),
+		hermes.WithCallTimeout(cfg.HermesCallTimeout),
+	}
+	if cfg.HermesTLS.Insecure {
+		logger.WarnContext(ctx, "Connecting to Hermes without TLS")
+	} else {
+		tlsConfig, tlsErr := hermes.LoadTLSConfig(cfg.HermesTLS.CAFile, cfg.HermesTLS.CertFile,
+			cfg.HermesTLS.KeyFile, cfg.HermesTLS.ServerName)
+		if tlsErr != nil {
+			log.Fatalf("Failed to load Hermes TLS configuration: %v", tlsErr)
+		}
+		hermesOpts = append(hermesOpts, hermes.WithTLS(tlsConfig))
+	}
+
+	hermesClient, hermesConn, err := hermes.NewClient(cfg.HermesAddr, hermesOpts...)
 	if err != nil {
 		log.Fatalf("Failed to connect to Hermes service: %v", err)
 	}
 	defer stop()
 	defer dtb.Close()
+	// Every retry of a write gets its own query timeout.
+	queryDB := repository.WithWriteRetry(
+		repository.WithQueryTimeout(dtb, cfg.Postgres.QueryTimeout), int(cfg.Postgres.WriteRetries))
+
+	var safeMode string
+	if err = repository.NewSchemaRepository(queryDB, appMetrics).CheckSchema(ctx); err != nil {
+		// The tables and columns added by this service come from migrations/, see `make migrate`.
+		if !cfg.SafeModeOnSchemaMismatch {
+			log.Fatalf("Database schema check failed, apply the pending migrations with 'make migrate': %v", err)
+		}
+		safeMode = err.Error()
+		logger.ErrorContext(ctx, "Database schema check failed, entering safe mode", "error", err)
+	}
+
+	employeeRepo := repository.NewEmployeeRepository(queryDB, appMetrics)
+	taskRepo := repository.NewTaskRepository(queryDB, appMetrics)
+	statRepo := repository.NewStatusRepository(queryDB, appMetrics)
+	// Both services share one database breaker, so a database outage pauses and resumes them together.
+	var dbHealth *outage.Coordinator
+	if cfg.DBBreakerThreshold > 0 {
+		dbHealth = outage.New(logger, "database", cfg.DBBreakerThreshold, dtb,
+			appMetrics.CircuitState.WithLabelValues("db", "shared"))
+	}
+	staffOpts := []employees.Option{
+		employees.WithRepeatedErrorLogEvery(cfg.RepeatedErrorLogEvery),
+		employees.WithSafeMode(safeMode),
+		employees.WithFailureTolerance(cfg.EmployeeFailureTolerance),
+		employees.WithPlaceholderEmailDomain(cfg.PlaceholderEmailDomain),
+		employees.WithDeactivateRemoved(cfg.DeactivateRemovedMaxShare),
+	}
+	if dbHealth != nil {
+		staffOpts = append(staffOpts, employees.WithDBCoordinator(dbHealth))
+	}
+	// Employees are only quarantined when failures are tolerated.
+	if cfg.EmployeeFailureTolerance > 0 {
+		staffOpts = append(staffOpts,
+			employees.WithQuarantineStore(repository.NewQuarantineRepository(queryDB, appMetrics)))
+	}
+	staff := employees.NewStaff(logger, employeeRepo, appMetrics, hermesClient, staffOpts...)
+	taskOpts := []tasks.Option{
+		tasks.WithCatchupStartDate(cfg.CatchupStartDate),
+		tasks.WithCatchupSkipToday(cfg.CatchupSkipToday),
+		tasks.WithCatchupWorkers(cfg.CatchupWorkers),
+		tasks.WithRepeatedErrorLogEvery(cfg.RepeatedErrorLogEvery),
+		tasks.WithSafeMode(safeMode),
+		tasks.WithIngestOnlyClosed(cfg.IngestOnlyClosed),
+	}
+	if cfg.PerDateHashes {
+		taskOpts = append(taskOpts, tasks.WithDateHashes(repository.NewDateHashRepository(queryDB, appMetrics)))
+	}
+	if cfg.TaskDeadLetterAfter > 0 {
+		taskOpts = append(taskOpts, tasks.WithDeadLetter(
+			repository.NewFailedTaskRepository(queryDB, appMetrics), cfg.TaskDeadLetterAfter))
+	}
+	if dbHealth != nil {
+		taskOpts = append(taskOpts, tasks.WithDBCoordinator(dbHealth))
+	}
+	taskService := tasks.NewTaskService(logger, taskRepo, statRepo, appMetrics, hermesClient, taskOpts...)
 
-	employeeRepo := repository.NewEmployeeRepository(dtb, appMetrics)
-	taskRepo := repository.NewTaskRepository(dtb, appMetrics)
-	statRepo := repository.NewStatusRepository(dtb, appMetrics)
-	staff := employees.NewStaff(logger, employeeRepo, appMetrics, hermesClient)
-	taskService := tasks.NewTaskService(logger, taskRepo, statRepo, appMetrics, hermesClient)
+	statusHandler := server.NewStatusHandler(logger, statRepo, map[string]server.ProgressReporter{
+		"employee": staff,
+		"task":     taskService,
+	})
 
 	wgr.Add(delta)
 
 	go func() {
 		defer wgr.Done()
 		serverPort := 8080
-		server.StartMonitoringServer(ctx, logger, reg, dtb, serverPort, hermesConn)
+		server.StartMonitoringServer(ctx, logger, reg, dtb, serverPort, hermesConn, safeMode, statusHandler,
+			cfg.EnablePprof)
 	}()
 
 	go func() {
@@ -97,6 +181,14 @@ func main() {
 
 	wgr.Wait()
 
+	if cfg.MetricsDumpPath != "" {
+		if err = metrics.DumpMetrics(reg, cfg.MetricsDumpPath); err != nil {
+			logger.ErrorContext(ctx, "Failed to dump metrics snapshot", "error", err)
+		} else {
+			logger.InfoContext(ctx, "Metrics snapshot written", "path", cfg.MetricsDumpPath)
+		}
+	}
+
 	logger.InfoContext(ctx, "Application stopped gracefully...")
 }
 
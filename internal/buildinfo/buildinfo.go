@@ -0,0 +1,27 @@
+// Package buildinfo holds the build metadata injected at link time, e.g.
+//
+//	go build -ldflags "-X github.com/UnknownOlympus/hephaestus/internal/buildinfo.Version=v1.2.3"
+package buildinfo
+
+// Build metadata, set via -ldflags -X. They keep their defaults in local builds.
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// Info is the build metadata of the running binary.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+}
+
+// Get returns the build metadata of the running binary.
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+	}
+}
@@ -2,13 +2,61 @@ package hermes
 
 import (
 	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/UnknownOlympus/hephaestus/internal/metrics"
 	pb "github.com/UnknownOlympus/olympus-protos/gen/go/scraper/olympus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 )
 
-func NewClient(grpcAddr string) (pb.ScraperServiceClient, *grpc.ClientConn, error) {
+const (
+	// reconnectMaxDelay caps the backoff between re-dials, so a restarted Hermes is found quickly.
+	reconnectMaxDelay = 10 * time.Second
+	// minConnectTimeout is how long a single dial attempt may take.
+	minConnectTimeout = 5 * time.Second
+	// keepaliveTime matches the default minimum ping interval enforced by gRPC servers.
+	keepaliveTime    = 5 * time.Minute
+	keepaliveTimeout = 20 * time.Second
+)
+
+// Option configures the Hermes client connection.
+type Option func(*options)
+
+type options struct {
+	interceptors []grpc.UnaryClientInterceptor
+	creds        credentials.TransportCredentials
+	log          *slog.Logger
+}
+
+// WithLogger sets the logger of the call log. Without it, the default slog logger is used.
+func WithLogger(log *slog.Logger) Option {
+	return func(o *options) {
+		o.log = log
+	}
+}
+
+// WithMetrics records the count, duration and errors of every call made to Hermes in metrics.
+func WithMetrics(metrics *metrics.Metrics) Option {
+	return func(o *options) {
+		o.interceptors = append(o.interceptors, UnaryMetricsInterceptor(metrics))
+	}
+}
+
+// WithCallTimeout bounds every call made to Hermes by timeout. Zero leaves calls unbounded.
+func WithCallTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		if timeout > 0 {
+			o.interceptors = append(o.interceptors, UnaryTimeoutInterceptor(timeout))
+		}
+	}
+}
+
+func NewClient(grpcAddr string, opts ...Option) (pb.ScraperServiceClient, *grpc.ClientConn, error) {
 	retrypolicy := `{
 		"methodConfig": [{
 			"name": [{}],
@@ -22,10 +70,24 @@ func NewClient(grpcAddr string) (pb.ScraperServiceClient, *grpc.ClientConn, erro
 		}]
 	}`
 
+	cfg := options{creds: insecure.NewCredentials(), log: slog.Default()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	// The call log wraps all other interceptors, so its duration covers the whole call.
+	cfg.interceptors = append([]grpc.UnaryClientInterceptor{UnaryLoggingInterceptor(cfg.log)}, cfg.interceptors...)
+	cfg.interceptors = append(cfg.interceptors, UnaryReconnectInterceptor())
+
+	backoffCfg := backoff.DefaultConfig
+	backoffCfg.MaxDelay = reconnectMaxDelay
+
 	conn, err := grpc.NewClient(
 		grpcAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(cfg.creds),
 		grpc.WithDefaultServiceConfig(retrypolicy),
+		grpc.WithConnectParams(grpc.ConnectParams{Backoff: backoffCfg, MinConnectTimeout: minConnectTimeout}),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{Time: keepaliveTime, Timeout: keepaliveTimeout}),
+		grpc.WithChainUnaryInterceptor(cfg.interceptors...),
 	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create grpc client: %w", err)
@@ -1,11 +1,20 @@
 package hermes_test
 
 import (
+	"context"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/UnknownOlympus/hephaestus/internal/client/hermes"
+	"github.com/UnknownOlympus/hephaestus/internal/metrics"
+	pb "github.com/UnknownOlympus/olympus-protos/gen/go/scraper/olympus"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestNewClient(t *testing.T) {
@@ -20,6 +29,16 @@ func TestNewClient(t *testing.T) {
 		assert.NotNil(t, conn)
 	})
 
+	t.Run("success - with metrics", func(t *testing.T) {
+		t.Parallel()
+		client, conn, err := hermes.NewClient("bufnet",
+			hermes.WithMetrics(metrics.NewMetrics(prometheus.NewRegistry())))
+
+		require.NoError(t, err)
+		assert.NotNil(t, client)
+		assert.NotNil(t, conn)
+	})
+
 	t.Run("error - failed to create client", func(t *testing.T) {
 		t.Parallel()
 		client, conn, err := hermes.NewClient("Segment%%2815197306101420000%29.ts")
@@ -30,3 +49,94 @@ func TestNewClient(t *testing.T) {
 		assert.Nil(t, conn)
 	})
 }
+
+type stubScraper struct {
+	pb.UnimplementedScraperServiceServer
+
+	// deadlines receives the deadline of every incoming call, if set.
+	deadlines chan time.Time
+}
+
+func (s stubScraper) GetTaskTypes(ctx context.Context, _ *pb.GetTaskTypesRequest) (*pb.GetTaskTypesResponse, error) {
+	if deadline, ok := ctx.Deadline(); ok && s.deadlines != nil {
+		s.deadlines <- deadline
+	}
+	return &pb.GetTaskTypesResponse{}, nil
+}
+
+// serveStubScraper serves a stub Hermes on addr and returns a function that stops it.
+func serveStubScraper(t *testing.T, addr string) func() {
+	t.Helper()
+
+	return serveScraper(t, addr, stubScraper{})
+}
+
+func serveScraper(t *testing.T, addr string, scraper pb.ScraperServiceServer) func() {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", addr)
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	pb.RegisterScraperServiceServer(srv, scraper)
+	go func() { _ = srv.Serve(lis) }()
+
+	return srv.Stop
+}
+
+func TestNewClient_ReconnectsAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+
+	stop := serveStubScraper(t, addr)
+	client, conn, err := hermes.NewClient(addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	callHermes := func() error {
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		defer cancel()
+		_, callErr := client.GetTaskTypes(ctx, &pb.GetTaskTypesRequest{})
+		return callErr
+	}
+
+	require.NoError(t, callHermes())
+
+	stop()
+	require.Equal(t, codes.Unavailable, status.Code(callHermes()))
+
+	stop = serveStubScraper(t, addr)
+	defer stop()
+	require.Eventually(t, func() bool { return callHermes() == nil }, 5*time.Second, 100*time.Millisecond)
+}
+
+func TestNewClient_CallTimeout(t *testing.T) {
+	t.Parallel()
+
+	scraper := stubScraper{deadlines: make(chan time.Time, 1)}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+	stop := serveScraper(t, addr, scraper)
+	defer stop()
+
+	client, conn, err := hermes.NewClient(addr, hermes.WithCallTimeout(3*time.Second))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sent := time.Now()
+	_, err = client.GetTaskTypes(t.Context(), &pb.GetTaskTypesRequest{})
+	require.NoError(t, err)
+
+	select {
+	case deadline := <-scraper.deadlines:
+		require.WithinDuration(t, sent.Add(3*time.Second), deadline, time.Second,
+			"the deadline is sent to Hermes with the call")
+	default:
+		t.Fatal("the call reached Hermes without a deadline")
+	}
+}
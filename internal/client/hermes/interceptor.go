@@ -0,0 +1,129 @@
+package hermes
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"path"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDKey is the metadata key that carries the id correlating a call in the logs of both services.
+const RequestIDKey = "x-request-id"
+
+// UnaryLoggingInterceptor returns a client interceptor that logs the method, duration, status code
+// and request id of every call at debug level. The request id is taken from the outgoing metadata,
+// or generated and sent to Hermes when there is none.
+func UnaryLoggingInterceptor(log *slog.Logger) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		fullMethod string,
+		req, reply any,
+		conn *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		var requestID string
+		if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(RequestIDKey)) > 0 {
+			requestID = md.Get(RequestIDKey)[0]
+		} else {
+			requestID = newRequestID()
+			ctx = metadata.AppendToOutgoingContext(ctx, RequestIDKey, requestID)
+		}
+		startTime := time.Now()
+
+		err := invoker(ctx, fullMethod, req, reply, conn, opts...)
+
+		log.DebugContext(ctx, "Hermes call completed",
+			"method", path.Base(fullMethod),
+			"duration", time.Since(startTime),
+			"code", status.Code(err).String(),
+			"request_id", requestID,
+		)
+
+		return err
+	}
+}
+
+// newRequestID returns a random 16-character hex id.
+func newRequestID() string {
+	const size = 8
+	buf := make([]byte, size)
+	_, _ = rand.Read(buf)
+
+	return hex.EncodeToString(buf)
+}
+
+// UnaryMetricsInterceptor returns a client interceptor that counts every call, observes its
+// duration and counts failed calls by status code, all labeled by the short method name.
+// The interceptor wraps the whole call, so the duration includes transparent retries.
+func UnaryMetricsInterceptor(metrics *metrics.Metrics) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		fullMethod string,
+		req, reply any,
+		conn *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		method := path.Base(fullMethod)
+		startTime := time.Now()
+
+		err := invoker(ctx, fullMethod, req, reply, conn, opts...)
+
+		metrics.HermesRequests.WithLabelValues(method).Inc()
+		metrics.HermesRequestDuration.WithLabelValues(method).Observe(time.Since(startTime).Seconds())
+		if err != nil {
+			metrics.HermesErrors.WithLabelValues(method, status.Code(err).String()).Inc()
+		}
+
+		return err
+	}
+}
+
+// UnaryReconnectInterceptor returns a client interceptor that re-dials Hermes as soon as a call
+// fails with Unavailable, instead of leaving the connection waiting for its reconnect backoff.
+// This way a restarted Hermes is picked up by the next scrape cycle.
+func UnaryReconnectInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		fullMethod string,
+		req, reply any,
+		conn *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		err := invoker(ctx, fullMethod, req, reply, conn, opts...)
+		if conn != nil && status.Code(err) == codes.Unavailable {
+			conn.ResetConnectBackoff()
+			conn.Connect()
+		}
+
+		return err
+	}
+}
+
+// UnaryTimeoutInterceptor returns a client interceptor that bounds every call by timeout. A deadline
+// already set on the context is kept when it is earlier. The deadline is sent to Hermes with the call.
+func UnaryTimeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		fullMethod string,
+		req, reply any,
+		conn *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return invoker(ctx, fullMethod, req, reply, conn, opts...)
+	}
+}
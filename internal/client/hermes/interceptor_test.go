@@ -0,0 +1,129 @@
+package hermes_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/client/hermes"
+	"github.com/UnknownOlympus/hephaestus/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryMetricsInterceptor(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	testMetrics := metrics.NewMetrics(reg)
+	interceptor := hermes.UnaryMetricsInterceptor(testMetrics)
+
+	succeed := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return nil
+	}
+	fail := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "hermes is down")
+	}
+
+	const method = "/olympus.ScraperService/GetEmployees"
+	require.NoError(t, interceptor(t.Context(), method, nil, nil, nil, succeed))
+	require.NoError(t, interceptor(t.Context(), method, nil, nil, nil, succeed))
+	err := interceptor(t.Context(), method, nil, nil, nil, fail)
+	require.Equal(t, codes.Unavailable, status.Code(err), "the invoker error is returned unchanged")
+
+	require.InDelta(t, 3, testutil.ToFloat64(testMetrics.HermesRequests.WithLabelValues("GetEmployees")), 0)
+	require.InDelta(t, 1,
+		testutil.ToFloat64(testMetrics.HermesErrors.WithLabelValues("GetEmployees", "Unavailable")), 0)
+	require.Equal(t, 1, testutil.CollectAndCount(testMetrics.HermesRequestDuration, "hephaestus_hermes_request_duration_seconds"))
+}
+
+func TestUnaryReconnectInterceptor(t *testing.T) {
+	t.Parallel()
+
+	interceptor := hermes.UnaryReconnectInterceptor()
+	fail := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "hermes is down")
+	}
+
+	err := interceptor(t.Context(), "/olympus.ScraperService/GetEmployees", nil, nil, nil, fail)
+
+	require.Equal(t, codes.Unavailable, status.Code(err), "the invoker error is returned unchanged")
+}
+
+func TestUnaryTimeoutInterceptor(t *testing.T) {
+	t.Parallel()
+
+	interceptor := hermes.UnaryTimeoutInterceptor(time.Minute)
+	var deadline time.Time
+	var hasDeadline bool
+	capture := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		deadline, hasDeadline = ctx.Deadline()
+		return nil
+	}
+
+	t.Run("sets the call deadline", func(t *testing.T) {
+		require.NoError(t, interceptor(t.Context(), "/olympus.ScraperService/GetEmployees", nil, nil, nil, capture))
+
+		require.True(t, hasDeadline)
+		require.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	})
+
+	t.Run("keeps an earlier deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		defer cancel()
+		want, _ := ctx.Deadline()
+
+		require.NoError(t, interceptor(ctx, "/olympus.ScraperService/GetEmployees", nil, nil, nil, capture))
+
+		require.Equal(t, want, deadline)
+	})
+}
+
+func TestUnaryLoggingInterceptor(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	interceptor := hermes.UnaryLoggingInterceptor(logger)
+
+	var sentID string
+	fail := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if ids := md.Get(hermes.RequestIDKey); len(ids) > 0 {
+			sentID = ids[0]
+		}
+		return status.Error(codes.Unavailable, "hermes is down")
+	}
+
+	t.Run("generates a request id", func(t *testing.T) {
+		buf.Reset()
+
+		err := interceptor(t.Context(), "/olympus.ScraperService/GetEmployees", nil, nil, nil, fail)
+
+		require.Equal(t, codes.Unavailable, status.Code(err), "the invoker error is returned unchanged")
+		require.Len(t, sentID, 16)
+		line := buf.String()
+		require.Contains(t, line, "level=DEBUG")
+		require.Contains(t, line, "method=GetEmployees")
+		require.Contains(t, line, "code=Unavailable")
+		require.Contains(t, line, "request_id="+sentID)
+	})
+
+	t.Run("keeps the request id of the caller", func(t *testing.T) {
+		buf.Reset()
+		ctx := metadata.AppendToOutgoingContext(t.Context(), hermes.RequestIDKey, "run-42")
+
+		_ = interceptor(ctx, "/olympus.ScraperService/GetDailyTasks", nil, nil, nil, fail)
+
+		require.Equal(t, "run-42", sentID)
+		require.Contains(t, buf.String(), "method=GetDailyTasks")
+		require.Contains(t, buf.String(), "request_id=run-42")
+	})
+}
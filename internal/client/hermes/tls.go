@@ -0,0 +1,56 @@
+package hermes
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// WithTLS makes the client connect to Hermes over TLS configured by tlsConfig.
+// Without it, the connection is insecure.
+func WithTLS(tlsConfig *tls.Config) Option {
+	return func(o *options) {
+		o.creds = credentials.NewTLS(tlsConfig)
+	}
+}
+
+// LoadTLSConfig builds the TLS configuration of the Hermes connection. Server certificates are verified
+// against the CA in caFile, or the system roots when it is empty. When certFile and keyFile are set,
+// the client presents that certificate for mutual TLS. A non-empty serverName overrides the name that
+// the server certificate is verified against.
+func LoadTLSConfig(caFile, certFile, keyFile, serverName string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+	}
+
+	if caFile != "" {
+		caPEM, err := os.ReadFile(filepath.Clean(caFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Hermes CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in Hermes CA file '%s'", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("both the client certificate and key are required for mutual TLS")
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Hermes client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
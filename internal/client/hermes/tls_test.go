@@ -0,0 +1,175 @@
+package hermes_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/client/hermes"
+	pb "github.com/UnknownOlympus/olympus-protos/gen/go/scraper/olympus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue signs a leaf certificate for localhost and returns it with its PEM-encoded certificate and key.
+func (ca *testCA) issue(t *testing.T, usage x509.ExtKeyUsage) (tls.Certificate, []byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	return cert, certPEM, keyPEM
+}
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// serveTLSStubScraper serves a stub Hermes over TLS and returns its address.
+func serveTLSStubScraper(t *testing.T, serverTLS *tls.Config) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(serverTLS)))
+	pb.RegisterScraperServiceServer(srv, stubScraper{})
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
+
+func TestNewClient_TLS(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t)
+	serverCert, _, _ := ca.issue(t, x509.ExtKeyUsageServerAuth)
+	_, clientCertPEM, clientKeyPEM := ca.issue(t, x509.ExtKeyUsageClientAuth)
+	caFile := writeFile(t, "ca.pem", ca.pem)
+	otherCAFile := writeFile(t, "other-ca.pem", newTestCA(t).pem)
+	clientCertFile := writeFile(t, "client.pem", clientCertPEM)
+	clientKeyFile := writeFile(t, "client-key.pem", clientKeyPEM)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	tlsAddr := serveTLSStubScraper(t, &tls.Config{Certificates: []tls.Certificate{serverCert}, MinVersion: tls.VersionTLS12})
+	mtlsAddr := serveTLSStubScraper(t, &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	})
+
+	tests := []struct {
+		name     string
+		addr     string
+		caFile   string
+		certFile string
+		keyFile  string
+		wantCode codes.Code
+	}{
+		{name: "valid CA", addr: tlsAddr, caFile: caFile, wantCode: codes.OK},
+		{name: "invalid CA", addr: tlsAddr, caFile: otherCAFile, wantCode: codes.Unavailable},
+		{
+			name: "mutual TLS", addr: mtlsAddr, caFile: caFile,
+			certFile: clientCertFile, keyFile: clientKeyFile, wantCode: codes.OK,
+		},
+		{name: "mutual TLS without client certificate", addr: mtlsAddr, caFile: caFile, wantCode: codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tlsConfig, err := hermes.LoadTLSConfig(tt.caFile, tt.certFile, tt.keyFile, "localhost")
+			require.NoError(t, err)
+			client, conn, err := hermes.NewClient(tt.addr, hermes.WithTLS(tlsConfig))
+			require.NoError(t, err)
+			defer conn.Close()
+
+			ctx, cancel := context.WithTimeout(t.Context(), 2*time.Second)
+			defer cancel()
+			_, err = client.GetTaskTypes(ctx, &pb.GetTaskTypesRequest{})
+
+			require.Equal(t, tt.wantCode, status.Code(err), err)
+		})
+	}
+}
+
+func TestLoadTLSConfig_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := hermes.LoadTLSConfig(filepath.Join(t.TempDir(), "missing.pem"), "", "", "")
+	require.ErrorContains(t, err, "failed to read Hermes CA certificate")
+
+	_, err = hermes.LoadTLSConfig(writeFile(t, "ca.pem", []byte("not a certificate")), "", "", "")
+	require.ErrorContains(t, err, "no certificates found")
+
+	_, err = hermes.LoadTLSConfig("", "client.pem", "", "")
+	require.ErrorContains(t, err, "both the client certificate and key are required")
+}
@@ -1,56 +1,462 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultInterval              = 10 * time.Minute
+	defaultCatchupStart          = "2024-01-01"
+	defaultRepeatedErrorLogEvery = 10
+	defaultMaxConns              = 10
+	defaultMinConns              = 3
+	defaultMaxConnIdleTime       = 30 * time.Second
+	defaultQueryTimeout          = 5 * time.Second
+	defaultWriteRetries          = 3
+	defaultDBBreakerThreshold    = 3
+	defaultHermesCallTimeout     = 10 * time.Second
 )
 
 type Config struct {
-	Env        string         `json:"env"`            // Env is the current environment: local, dev, prod.
-	Postgres   PostgresConfig `json:"postgres"`       // Postgres holds the database configuration
-	Interval   time.Duration  `json:"interval"`       // Interal is the time after that parser will update info.
-	HermesAddr string         `json:"hermes_address"` //
+	Env        string         `json:"env" yaml:"env"`                       // Env is the current environment: local, dev, prod.
+	Postgres   PostgresConfig `json:"postgres" yaml:"postgres"`             // Postgres holds the database configuration
+	Interval   time.Duration  `json:"interval" yaml:"interval"`             // Interal is the time after that parser will update info.
+	HermesAddr string         `json:"hermes_address" yaml:"hermes_address"` //
+	// CatchupStartDate is the date where catch-up starts when nothing was processed yet.
+	CatchupStartDate time.Time `json:"catchup_start_date" yaml:"-"`
+	// CatchupSkipToday stops catch-up at yesterday and leaves today to the maintenance loop.
+	CatchupSkipToday bool `json:"catchup_skip_today" yaml:"catchup_skip_today"`
+	// CatchupWorkers is the number of days catch-up processes concurrently. One or less is sequential.
+	CatchupWorkers int `json:"catchup_workers" yaml:"catchup_workers"`
+	// RepeatedErrorLogEvery controls how often an identical repeated run error is logged again.
+	RepeatedErrorLogEvery int `json:"repeated_error_log_every" yaml:"repeated_error_log_every"`
+	// MetricsDumpPath is the file where a metrics snapshot is written on shutdown. Empty disables it.
+	MetricsDumpPath string `json:"metrics_dump_path" yaml:"metrics_dump_path"`
+	// DBBreakerThreshold is the number of consecutive runs failing on the database after which
+	// writes are paused until the database is reachable again. Zero disables the breaker.
+	DBBreakerThreshold int `json:"db_breaker_threshold" yaml:"db_breaker_threshold"`
+	// PerDateHashes stores the Hermes hash of every processed date instead of a single rolling hash.
+	PerDateHashes bool `json:"per_date_hashes" yaml:"per_date_hashes"`
+	// SafeModeOnSchemaMismatch keeps the service running in read-only safe mode instead of exiting
+	// when the database schema does not match what the service expects.
+	SafeModeOnSchemaMismatch bool `json:"safe_mode_on_schema_mismatch" yaml:"safe_mode_on_schema_mismatch"`
+	// EmployeeFailureTolerance is the number of employees that may fail to save in a run without failing it.
+	EmployeeFailureTolerance int `json:"employee_failure_tolerance" yaml:"employee_failure_tolerance"`
+	// IngestOnlyClosed makes the task service skip tasks that are not closed yet.
+	IngestOnlyClosed bool `json:"ingest_only_closed" yaml:"ingest_only_closed"`
+	// TaskDeadLetterAfter is the number of save attempts within a run after which a failing task is
+	// moved to the dead-letter queue so that the rest of its day can be stored. Zero disables the queue.
+	TaskDeadLetterAfter int `json:"task_dead_letter_after" yaml:"task_dead_letter_after"`
+	// DeactivateRemovedMaxShare enables marking employees missing from Hermes as dismissed. It is the
+	// largest share of active employees, between 0 and 1, deactivated at once. Zero disables it.
+	DeactivateRemovedMaxShare float64 `json:"deactivate_removed_max_share" yaml:"deactivate_removed_max_share"`
+	// PlaceholderEmailDomain is the domain of the addresses generated for employees without a valid
+	// email. Empty generates random addresses.
+	PlaceholderEmailDomain string `json:"placeholder_email_domain" yaml:"placeholder_email_domain"`
+	// HermesCallTimeout bounds every call made to Hermes. Zero disables it.
+	HermesCallTimeout time.Duration `json:"hermes_call_timeout" yaml:"hermes_call_timeout"`
+	// HermesTLS configures the TLS connection to Hermes.
+	HermesTLS HermesTLSConfig `json:"hermes_tls" yaml:"hermes_tls"`
+	// EnablePprof registers the net/http/pprof handlers under /debug/pprof/ on the monitoring server.
+	EnablePprof bool `json:"enable_pprof" yaml:"enable_pprof"`
 }
 
 // PostgresConfig struct holds the configuration details for connecting to a PostgreSQL database.
 type PostgresConfig struct {
-	Host     string `json:"host"`     // Host is the database server address.
-	Port     string `json:"port"`     // Port is the database server port.
-	User     string `json:"user"`     // User is the database user.
-	Password string `json:"password"` // Password is the database user's password.
-	Dbname   string `json:"db_name"`  // Dbname is the name of the database.
+	Host     string `json:"host" yaml:"host"`         // Host is the database server address.
+	Port     string `json:"port" yaml:"port"`         // Port is the database server port.
+	User     string `json:"user" yaml:"user"`         // User is the database user.
+	Password string `json:"password" yaml:"password"` // Password is the database user's password.
+	Dbname   string `json:"db_name" yaml:"db_name"`   // Dbname is the name of the database.
+	// MaxConns is the maximum number of connections in the pool.
+	MaxConns int32 `json:"max_conns" yaml:"max_conns"`
+	// MinConns is the minimum number of connections kept open in the pool.
+	MinConns int32 `json:"min_conns" yaml:"min_conns"`
+	// MaxConnIdleTime is the duration after which an idle connection is closed.
+	MaxConnIdleTime time.Duration `json:"max_conn_idle_time" yaml:"max_conn_idle_time"`
+	// QueryTimeout bounds every repository query and transaction, so that a hung connection cannot block a run.
+	// Zero disables it.
+	QueryTimeout time.Duration `json:"query_timeout" yaml:"query_timeout"`
+	// WriteRetries is the number of times a write failing on a transient error is retried.
+	// Zero disables retries.
+	WriteRetries int32 `json:"write_retries" yaml:"write_retries"`
+}
+
+// HermesTLSConfig holds the TLS settings of the Hermes connection.
+type HermesTLSConfig struct {
+	// CAFile is the CA certificate that the Hermes certificate is verified against.
+	// Empty uses the system roots.
+	CAFile string `json:"ca_file" yaml:"ca_file"`
+	// CertFile and KeyFile are the client certificate and key presented for mutual TLS. Empty disables mTLS.
+	CertFile string `json:"cert_file" yaml:"cert_file"`
+	KeyFile  string `json:"key_file" yaml:"key_file"`
+	// ServerName overrides the name the Hermes certificate is verified against.
+	ServerName string `json:"server_name" yaml:"server_name"`
+	// Insecure connects to Hermes without TLS. It has to be enabled explicitly.
+	Insecure bool `json:"insecure" yaml:"insecure"`
 }
 
-// MustLoad loads the configuration from a YAML file and returns a Config struct.
+// fileConfig is the on-disk representation of Config. Durations and dates are kept
+// as human-readable strings ("10m", "2024-01-01") and parsed after decoding.
+type fileConfig struct {
+	*Config
+
+	Interval          string              `json:"interval"`
+	CatchupStartDate  string              `json:"catchup_start_date"`
+	HermesCallTimeout string              `json:"hermes_call_timeout"`
+	Postgres          *filePostgresConfig `json:"postgres"`
+}
+
+// filePostgresConfig is the on-disk representation of PostgresConfig.
+type filePostgresConfig struct {
+	*PostgresConfig
+
+	MaxConnIdleTime string `json:"max_conn_idle_time"`
+	QueryTimeout    string `json:"query_timeout"`
+}
+
+// MustLoad loads the configuration and returns a Config struct.
+// Values are taken from the defaults, then from the optional JSON or YAML file
+// pointed to by HEPHAESTUS_CONFIG_FILE, and finally from environment variables,
+// which take precedence over the file.
 func MustLoad() *Config {
 	_ = godotenv.Load()
 
-	interval, err := time.ParseDuration(setDeafultEnv("HEPHAESTUS_INTERVAL", "10m"))
+	cfg := &Config{
+		Env:                   "production",
+		Interval:              defaultInterval,
+		RepeatedErrorLogEvery: defaultRepeatedErrorLogEvery,
+		DBBreakerThreshold:    defaultDBBreakerThreshold,
+		HermesCallTimeout:     defaultHermesCallTimeout,
+		Postgres: PostgresConfig{
+			MaxConns:        defaultMaxConns,
+			MinConns:        defaultMinConns,
+			MaxConnIdleTime: defaultMaxConnIdleTime,
+			QueryTimeout:    defaultQueryTimeout,
+			WriteRetries:    defaultWriteRetries,
+		},
+	}
+
+	var err error
+	cfg.CatchupStartDate, err = time.Parse(time.DateOnly, defaultCatchupStart)
 	if err != nil {
-		panic("failed to parse interval from configuration")
+		panic("failed to parse default catch-up start date")
 	}
 
-	return &Config{
-		Env: setDeafultEnv("HEPHAESTUS_ENV", "production"),
-		Postgres: PostgresConfig{
-			Host:     os.Getenv("DB_HOST"),
-			Port:     os.Getenv("DB_PORT"),
-			User:     os.Getenv("DB_USERNAME"),
-			Password: os.Getenv("DB_PASSWORD"),
-			Dbname:   os.Getenv("DB_NAME"),
-		},
-		Interval:   interval,
-		HermesAddr: os.Getenv("HERMES_ADDRESS"),
+	if path, ok := lookupEnv("HEPHAESTUS_CONFIG_FILE"); ok {
+		if err = loadFile(path, cfg); err != nil {
+			panic(err.Error())
+		}
+	}
+
+	applyEnv(cfg)
+
+	if err = cfg.validate(); err != nil {
+		panic(err.Error())
+	}
+
+	return cfg
+}
+
+// loadFile decodes a JSON or YAML configuration file into cfg. The format is chosen by file extension.
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return loadJSON(data, cfg)
+	case ".yaml", ".yml":
+		return loadYAML(data, cfg)
+	default:
+		return fmt.Errorf("unsupported configuration file format '%s': expected .json, .yaml or .yml", path)
+	}
+}
+
+// loadJSON decodes a JSON configuration file into cfg.
+func loadJSON(data []byte, cfg *Config) error {
+	file := fileConfig{Config: cfg, Postgres: &filePostgresConfig{PostgresConfig: &cfg.Postgres}}
+	err := json.Unmarshal(data, &file)
+	if err != nil {
+		return fmt.Errorf("failed to decode configuration file: %w", err)
+	}
+
+	if file.Postgres.MaxConnIdleTime != "" {
+		if cfg.Postgres.MaxConnIdleTime, err = time.ParseDuration(file.Postgres.MaxConnIdleTime); err != nil {
+			return fmt.Errorf("failed to parse max connection idle time from configuration file: %w", err)
+		}
+	}
+
+	if file.Postgres.QueryTimeout != "" {
+		if cfg.Postgres.QueryTimeout, err = time.ParseDuration(file.Postgres.QueryTimeout); err != nil {
+			return fmt.Errorf("failed to parse query timeout from configuration file: %w", err)
+		}
+	}
+
+	if file.Interval != "" {
+		if cfg.Interval, err = time.ParseDuration(file.Interval); err != nil {
+			return fmt.Errorf("failed to parse interval from configuration file: %w", err)
+		}
+	}
+
+	if file.HermesCallTimeout != "" {
+		if cfg.HermesCallTimeout, err = time.ParseDuration(file.HermesCallTimeout); err != nil {
+			return fmt.Errorf("failed to parse Hermes call timeout from configuration file: %w", err)
+		}
+	}
+
+	return setCatchupStartDate(cfg, file.CatchupStartDate)
+}
+
+// loadYAML decodes a YAML configuration file into cfg. YAML is decoded directly rather than
+// through JSON, so unquoted scalars such as a numeric port or a date keep working: the YAML
+// decoder stores any scalar in a string field and parses durations itself.
+func loadYAML(data []byte, cfg *Config) error {
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("failed to decode configuration file: %w", err)
+	}
+
+	// An unquoted date is a YAML timestamp, so the date is read as its raw text and parsed here.
+	var dates struct {
+		CatchupStartDate string `yaml:"catchup_start_date"`
+	}
+	if err := yaml.Unmarshal(data, &dates); err != nil {
+		return fmt.Errorf("failed to decode configuration file: %w", err)
+	}
+
+	return setCatchupStartDate(cfg, dates.CatchupStartDate)
+}
+
+// setCatchupStartDate parses value, a date ("2024-01-01") or an RFC 3339 time, into the catch-up
+// start date of cfg. An empty value keeps the current date.
+func setCatchupStartDate(cfg *Config, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		var timeErr error
+		if date, timeErr = time.Parse(time.RFC3339, value); timeErr != nil {
+			return fmt.Errorf("failed to parse catch-up start date from configuration file: %w", err)
+		}
+	}
+	cfg.CatchupStartDate = date
+
+	return nil
+}
+
+// applyEnv overrides cfg with the values of all environment variables that are set.
+func applyEnv(cfg *Config) {
+	var err error
+
+	overrideString("HEPHAESTUS_ENV", &cfg.Env)
+	overrideString("DB_HOST", &cfg.Postgres.Host)
+	overrideString("DB_PORT", &cfg.Postgres.Port)
+	overrideString("DB_USERNAME", &cfg.Postgres.User)
+	overrideString("DB_PASSWORD", &cfg.Postgres.Password)
+	overrideString("DB_NAME", &cfg.Postgres.Dbname)
+	overrideString("HERMES_ADDRESS", &cfg.HermesAddr)
+	overrideString("HEPHAESTUS_METRICS_DUMP_PATH", &cfg.MetricsDumpPath)
+	overrideString("HEPHAESTUS_PLACEHOLDER_EMAIL_DOMAIN", &cfg.PlaceholderEmailDomain)
+	overrideString("HERMES_TLS_CA_FILE", &cfg.HermesTLS.CAFile)
+	overrideString("HERMES_TLS_CERT_FILE", &cfg.HermesTLS.CertFile)
+	overrideString("HERMES_TLS_KEY_FILE", &cfg.HermesTLS.KeyFile)
+	overrideString("HERMES_TLS_SERVER_NAME", &cfg.HermesTLS.ServerName)
+
+	overrideInt32("DB_MAX_CONNS", &cfg.Postgres.MaxConns)
+	overrideInt32("DB_MIN_CONNS", &cfg.Postgres.MinConns)
+	overrideInt32("DB_WRITE_RETRIES", &cfg.Postgres.WriteRetries)
+
+	if value, ok := lookupEnv("DB_MAX_CONN_IDLE_TIME"); ok {
+		if cfg.Postgres.MaxConnIdleTime, err = time.ParseDuration(value); err != nil {
+			panic("failed to parse DB_MAX_CONN_IDLE_TIME from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("DB_QUERY_TIMEOUT"); ok {
+		if cfg.Postgres.QueryTimeout, err = time.ParseDuration(value); err != nil {
+			panic("failed to parse DB_QUERY_TIMEOUT from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HERMES_CALL_TIMEOUT"); ok {
+		if cfg.HermesCallTimeout, err = time.ParseDuration(value); err != nil {
+			panic("failed to parse HERMES_CALL_TIMEOUT from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HEPHAESTUS_INTERVAL"); ok {
+		if cfg.Interval, err = time.ParseDuration(value); err != nil {
+			panic("failed to parse interval from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HEPHAESTUS_CATCHUP_START"); ok {
+		if cfg.CatchupStartDate, err = time.Parse(time.DateOnly, value); err != nil {
+			panic("failed to parse catch-up start date from configuration, expected format 2006-01-02")
+		}
+	}
+
+	if value, ok := lookupEnv("HEPHAESTUS_CATCHUP_SKIP_TODAY"); ok {
+		if cfg.CatchupSkipToday, err = strconv.ParseBool(value); err != nil {
+			panic("failed to parse HEPHAESTUS_CATCHUP_SKIP_TODAY from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HEPHAESTUS_CATCHUP_WORKERS"); ok {
+		if cfg.CatchupWorkers, err = strconv.Atoi(value); err != nil {
+			panic("failed to parse HEPHAESTUS_CATCHUP_WORKERS from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HEPHAESTUS_REPEATED_ERROR_LOG_EVERY"); ok {
+		if cfg.RepeatedErrorLogEvery, err = strconv.Atoi(value); err != nil {
+			panic("failed to parse repeated error log frequency from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HEPHAESTUS_PER_DATE_HASHES"); ok {
+		if cfg.PerDateHashes, err = strconv.ParseBool(value); err != nil {
+			panic("failed to parse HEPHAESTUS_PER_DATE_HASHES from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HEPHAESTUS_SAFE_MODE_ON_SCHEMA_MISMATCH"); ok {
+		if cfg.SafeModeOnSchemaMismatch, err = strconv.ParseBool(value); err != nil {
+			panic("failed to parse HEPHAESTUS_SAFE_MODE_ON_SCHEMA_MISMATCH from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HEPHAESTUS_EMPLOYEE_FAILURE_TOLERANCE"); ok {
+		if cfg.EmployeeFailureTolerance, err = strconv.Atoi(value); err != nil {
+			panic("failed to parse employee failure tolerance from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HEPHAESTUS_DEACTIVATE_REMOVED_MAX_SHARE"); ok {
+		if cfg.DeactivateRemovedMaxShare, err = strconv.ParseFloat(value, 64); err != nil {
+			panic("failed to parse HEPHAESTUS_DEACTIVATE_REMOVED_MAX_SHARE from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HEPHAESTUS_INGEST_ONLY_CLOSED"); ok {
+		if cfg.IngestOnlyClosed, err = strconv.ParseBool(value); err != nil {
+			panic("failed to parse HEPHAESTUS_INGEST_ONLY_CLOSED from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HEPHAESTUS_TASK_DEAD_LETTER_AFTER"); ok {
+		if cfg.TaskDeadLetterAfter, err = strconv.Atoi(value); err != nil {
+			panic("failed to parse HEPHAESTUS_TASK_DEAD_LETTER_AFTER from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HERMES_INSECURE"); ok {
+		if cfg.HermesTLS.Insecure, err = strconv.ParseBool(value); err != nil {
+			panic("failed to parse HERMES_INSECURE from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HEPHAESTUS_ENABLE_PPROF"); ok {
+		if cfg.EnablePprof, err = strconv.ParseBool(value); err != nil {
+			panic("failed to parse HEPHAESTUS_ENABLE_PPROF from configuration")
+		}
+	}
+
+	if value, ok := lookupEnv("HEPHAESTUS_DB_BREAKER_THRESHOLD"); ok {
+		if cfg.DBBreakerThreshold, err = strconv.Atoi(value); err != nil {
+			panic("failed to parse database breaker threshold from configuration")
+		}
+	}
+}
+
+// validate checks that all required configuration fields are set.
+func (c *Config) validate() error {
+	required := []struct {
+		env   string
+		value string
+	}{
+		{"HERMES_ADDRESS", c.HermesAddr},
+		{"DB_HOST", c.Postgres.Host},
+		{"DB_PORT", c.Postgres.Port},
+		{"DB_USERNAME", c.Postgres.User},
+		{"DB_NAME", c.Postgres.Dbname},
+	}
+
+	for _, field := range required {
+		if field.value == "" {
+			return fmt.Errorf("missing required configuration: %s is not set", field.env)
+		}
+	}
+
+	if c.DeactivateRemovedMaxShare < 0 || c.DeactivateRemovedMaxShare > 1 {
+		return fmt.Errorf("invalid configuration: HEPHAESTUS_DEACTIVATE_REMOVED_MAX_SHARE must be between 0 and 1, got %v",
+			c.DeactivateRemovedMaxShare)
+	}
+
+	if c.CatchupWorkers < 0 {
+		return fmt.Errorf("invalid configuration: HEPHAESTUS_CATCHUP_WORKERS must not be negative, got %d",
+			c.CatchupWorkers)
+	}
+
+	if c.TaskDeadLetterAfter < 0 {
+		return fmt.Errorf("invalid configuration: HEPHAESTUS_TASK_DEAD_LETTER_AFTER must not be negative, got %d",
+			c.TaskDeadLetterAfter)
+	}
+
+	if c.PlaceholderEmailDomain != "" && !strings.Contains(c.PlaceholderEmailDomain, ".") {
+		return fmt.Errorf("invalid configuration: HEPHAESTUS_PLACEHOLDER_EMAIL_DOMAIN '%s' is not a domain",
+			c.PlaceholderEmailDomain)
+	}
+
+	if (c.HermesTLS.CertFile == "") != (c.HermesTLS.KeyFile == "") {
+		return errors.New("invalid configuration: HERMES_TLS_CERT_FILE and HERMES_TLS_KEY_FILE must be set together")
+	}
+
+	return nil
+}
+
+// overrideString replaces dst with the value of the environment variable key, if it is set.
+func overrideString(key string, dst *string) {
+	if value, ok := lookupEnv(key); ok {
+		*dst = value
+	}
+}
+
+// overrideInt32 replaces dst with the value of the environment variable key, if it is set.
+func overrideInt32(key string, dst *int32) {
+	value, ok := lookupEnv(key)
+	if !ok {
+		return
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse %s from configuration", key))
 	}
+	*dst = int32(parsed)
 }
 
-func setDeafultEnv(key, override string) string {
+// lookupEnv returns the value of the environment variable key. Variables that are set
+// to an empty string are treated as unset.
+func lookupEnv(key string) (string, bool) {
 	value, exists := os.LookupEnv(key)
-	if !exists {
-		value = override
+	if !exists || value == "" {
+		return "", false
 	}
 
-	return value
+	return value, true
 }
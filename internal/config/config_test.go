@@ -1,6 +1,8 @@
 package config_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -28,6 +30,62 @@ func Test_MustLoadFromFile(t *testing.T) {
 	assert.Equal(t, "testName", cfg.Postgres.Dbname)
 	assert.Equal(t, 10*time.Minute, cfg.Interval)
 	assert.Equal(t, "testAddr", cfg.HermesAddr)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), cfg.CatchupStartDate)
+	assert.Equal(t, 10, cfg.RepeatedErrorLogEvery)
+	assert.Equal(t, 3, cfg.DBBreakerThreshold)
+	assert.Equal(t, int32(10), cfg.Postgres.MaxConns)
+	assert.Equal(t, int32(3), cfg.Postgres.MinConns)
+	assert.Equal(t, 30*time.Second, cfg.Postgres.MaxConnIdleTime)
+	assert.Equal(t, 5*time.Second, cfg.Postgres.QueryTimeout)
+	assert.Equal(t, int32(3), cfg.Postgres.WriteRetries)
+	assert.Equal(t, 10*time.Second, cfg.HermesCallTimeout)
+}
+
+func TestMustLoad_PoolSettings(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("custom values", func(t *testing.T) {
+		t.Setenv("DB_MAX_CONNS", "50")
+		t.Setenv("DB_MIN_CONNS", "5")
+		t.Setenv("DB_MAX_CONN_IDLE_TIME", "2m")
+		t.Setenv("DB_QUERY_TIMEOUT", "1500ms")
+		t.Setenv("DB_WRITE_RETRIES", "0")
+
+		cfg := config.MustLoad()
+
+		assert.Equal(t, int32(50), cfg.Postgres.MaxConns)
+		assert.Equal(t, int32(5), cfg.Postgres.MinConns)
+		assert.Equal(t, 2*time.Minute, cfg.Postgres.MaxConnIdleTime)
+		assert.Equal(t, 1500*time.Millisecond, cfg.Postgres.QueryTimeout)
+		assert.Equal(t, int32(0), cfg.Postgres.WriteRetries)
+	})
+
+	t.Run("malformed max conns", func(t *testing.T) {
+		t.Setenv("DB_MAX_CONNS", "many")
+
+		assert.PanicsWithValue(t, "failed to parse DB_MAX_CONNS from configuration", func() {
+			config.MustLoad()
+		})
+	})
+
+	t.Run("malformed idle time", func(t *testing.T) {
+		t.Setenv("DB_MAX_CONN_IDLE_TIME", "forever")
+
+		assert.PanicsWithValue(t, "failed to parse DB_MAX_CONN_IDLE_TIME from configuration", func() {
+			config.MustLoad()
+		})
+	})
+	t.Run("malformed query timeout", func(t *testing.T) {
+		t.Setenv("DB_QUERY_TIMEOUT", "soon")
+
+		assert.PanicsWithValue(t, "failed to parse DB_QUERY_TIMEOUT from configuration", func() {
+			config.MustLoad()
+		})
+	})
 }
 
 func TestMustLoad_IntervalError(t *testing.T) {
@@ -37,3 +95,528 @@ func TestMustLoad_IntervalError(t *testing.T) {
 		config.MustLoad()
 	})
 }
+
+func TestMustLoad_MissingRequired(t *testing.T) {
+	required := map[string]string{
+		"HERMES_ADDRESS": "testAddr",
+		"DB_HOST":        "testHost",
+		"DB_PORT":        "12345",
+		"DB_USERNAME":    "admin",
+		"DB_NAME":        "testName",
+	}
+
+	tests := []struct {
+		name    string
+		missing string
+	}{
+		{name: "missing hermes address", missing: "HERMES_ADDRESS"},
+		{name: "missing db host", missing: "DB_HOST"},
+		{name: "missing db port", missing: "DB_PORT"},
+		{name: "missing db username", missing: "DB_USERNAME"},
+		{name: "missing db name", missing: "DB_NAME"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range required {
+				if key == tt.missing {
+					value = ""
+				}
+				t.Setenv(key, value)
+			}
+
+			assert.PanicsWithValue(t, "missing required configuration: "+tt.missing+" is not set", func() {
+				config.MustLoad()
+			})
+		})
+	}
+}
+
+func TestMustLoad_CatchupStartDate(t *testing.T) {
+	setRequired := func(t *testing.T) {
+		t.Helper()
+		t.Setenv("DB_HOST", "testHost")
+		t.Setenv("DB_PORT", "12345")
+		t.Setenv("DB_USERNAME", "admin")
+		t.Setenv("DB_NAME", "testName")
+		t.Setenv("HERMES_ADDRESS", "testAddr")
+	}
+
+	t.Run("valid value", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("HEPHAESTUS_CATCHUP_START", "2023-06-15")
+
+		cfg := config.MustLoad()
+
+		assert.Equal(t, time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC), cfg.CatchupStartDate)
+	})
+
+	t.Run("empty value falls back to default", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("HEPHAESTUS_CATCHUP_START", "")
+
+		cfg := config.MustLoad()
+
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), cfg.CatchupStartDate)
+	})
+
+	t.Run("malformed value", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("HEPHAESTUS_CATCHUP_START", "15.06.2023")
+
+		assert.PanicsWithValue(t,
+			"failed to parse catch-up start date from configuration, expected format 2006-01-02",
+			func() {
+				config.MustLoad()
+			})
+	})
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	return path
+}
+
+func TestMustLoad_FromFile(t *testing.T) {
+	t.Run("json file", func(t *testing.T) {
+		path := writeConfigFile(t, "config.json", `{
+			"env": "development",
+			"interval": "5m",
+			"hermes_address": "hermes:50051",
+			"catchup_start_date": "2023-02-01",
+			"hermes_call_timeout": "30s",
+			"postgres": {"host": "db", "port": "5432", "user": "file_user", "password": "secret", "db_name": "olympus"}
+		}`)
+		t.Setenv("HEPHAESTUS_CONFIG_FILE", path)
+
+		cfg := config.MustLoad()
+
+		assert.Equal(t, "development", cfg.Env)
+		assert.Equal(t, 5*time.Minute, cfg.Interval)
+		assert.Equal(t, "hermes:50051", cfg.HermesAddr)
+		assert.Equal(t, time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), cfg.CatchupStartDate)
+		assert.Equal(t, 30*time.Second, cfg.HermesCallTimeout)
+		assert.Equal(t, "db", cfg.Postgres.Host)
+		assert.Equal(t, "5432", cfg.Postgres.Port)
+		assert.Equal(t, "file_user", cfg.Postgres.User)
+		assert.Equal(t, "secret", cfg.Postgres.Password)
+		assert.Equal(t, "olympus", cfg.Postgres.Dbname)
+	})
+
+	t.Run("yaml file with env overrides", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", `
+env: development
+interval: 5m
+hermes_address: hermes:50051
+postgres:
+  host: db
+  port: "5432"
+  user: file_user
+  db_name: olympus
+`)
+		t.Setenv("HEPHAESTUS_CONFIG_FILE", path)
+		t.Setenv("HEPHAESTUS_INTERVAL", "1m")
+		t.Setenv("DB_USERNAME", "env_user")
+
+		cfg := config.MustLoad()
+
+		assert.Equal(t, "development", cfg.Env)
+		assert.Equal(t, time.Minute, cfg.Interval)
+		assert.Equal(t, "hermes:50051", cfg.HermesAddr)
+		assert.Equal(t, "db", cfg.Postgres.Host)
+		assert.Equal(t, "env_user", cfg.Postgres.User)
+	})
+
+	t.Run("yaml file with unquoted scalars", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yml", `
+env: development
+interval: 5m
+hermes_address: hermes:50051
+catchup_start_date: 2023-02-01
+hermes_call_timeout: 30s
+postgres:
+  host: db
+  port: 5432
+  user: file_user
+  db_name: olympus
+  max_conns: 8
+`)
+		t.Setenv("HEPHAESTUS_CONFIG_FILE", path)
+
+		cfg := config.MustLoad()
+
+		assert.Equal(t, time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), cfg.CatchupStartDate)
+		assert.Equal(t, 30*time.Second, cfg.HermesCallTimeout)
+		assert.Equal(t, "5432", cfg.Postgres.Port)
+		assert.Equal(t, int32(8), cfg.Postgres.MaxConns)
+	})
+
+	t.Run("unsupported file format", func(t *testing.T) {
+		path := writeConfigFile(t, "config.toml", `env = "local"`)
+		t.Setenv("HEPHAESTUS_CONFIG_FILE", path)
+
+		assert.Panics(t, func() {
+			config.MustLoad()
+		})
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_CONFIG_FILE", filepath.Join(t.TempDir(), "missing.json"))
+
+		assert.Panics(t, func() {
+			config.MustLoad()
+		})
+	})
+}
+
+func TestMustLoad_CatchupSkipToday(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("disabled by default", func(t *testing.T) {
+		cfg := config.MustLoad()
+
+		assert.False(t, cfg.CatchupSkipToday)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_CATCHUP_SKIP_TODAY", "true")
+
+		cfg := config.MustLoad()
+
+		assert.True(t, cfg.CatchupSkipToday)
+	})
+}
+
+func TestMustLoad_PerDateHashes(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("disabled by default", func(t *testing.T) {
+		cfg := config.MustLoad()
+
+		assert.False(t, cfg.PerDateHashes)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_PER_DATE_HASHES", "true")
+
+		cfg := config.MustLoad()
+
+		assert.True(t, cfg.PerDateHashes)
+	})
+}
+
+func TestMustLoad_DBBreakerThreshold(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("custom value", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_DB_BREAKER_THRESHOLD", "5")
+
+		cfg := config.MustLoad()
+
+		assert.Equal(t, 5, cfg.DBBreakerThreshold)
+	})
+
+	t.Run("malformed value", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_DB_BREAKER_THRESHOLD", "often")
+
+		assert.PanicsWithValue(t, "failed to parse database breaker threshold from configuration", func() {
+			config.MustLoad()
+		})
+	})
+}
+
+func TestMustLoad_SafeModeOnSchemaMismatch(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("disabled by default", func(t *testing.T) {
+		cfg := config.MustLoad()
+
+		assert.False(t, cfg.SafeModeOnSchemaMismatch)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_SAFE_MODE_ON_SCHEMA_MISMATCH", "true")
+
+		cfg := config.MustLoad()
+
+		assert.True(t, cfg.SafeModeOnSchemaMismatch)
+	})
+}
+
+func TestMustLoad_EmployeeFailureTolerance(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("no tolerance by default", func(t *testing.T) {
+		cfg := config.MustLoad()
+
+		assert.Equal(t, 0, cfg.EmployeeFailureTolerance)
+	})
+
+	t.Run("custom value", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_EMPLOYEE_FAILURE_TOLERANCE", "2")
+
+		cfg := config.MustLoad()
+
+		assert.Equal(t, 2, cfg.EmployeeFailureTolerance)
+	})
+}
+
+func TestMustLoad_IngestOnlyClosed(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("disabled by default", func(t *testing.T) {
+		cfg := config.MustLoad()
+
+		assert.False(t, cfg.IngestOnlyClosed)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_INGEST_ONLY_CLOSED", "true")
+
+		cfg := config.MustLoad()
+
+		assert.True(t, cfg.IngestOnlyClosed)
+	})
+}
+
+func TestMustLoad_EnablePprof(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("disabled by default", func(t *testing.T) {
+		cfg := config.MustLoad()
+
+		assert.False(t, cfg.EnablePprof)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_ENABLE_PPROF", "true")
+
+		cfg := config.MustLoad()
+
+		assert.True(t, cfg.EnablePprof)
+	})
+}
+
+func TestMustLoad_HermesTLS(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("TLS by default", func(t *testing.T) {
+		cfg := config.MustLoad()
+
+		assert.Equal(t, config.HermesTLSConfig{}, cfg.HermesTLS)
+	})
+
+	t.Run("mutual TLS", func(t *testing.T) {
+		t.Setenv("HERMES_TLS_CA_FILE", "/etc/hermes/ca.pem")
+		t.Setenv("HERMES_TLS_CERT_FILE", "/etc/hermes/client.pem")
+		t.Setenv("HERMES_TLS_KEY_FILE", "/etc/hermes/client-key.pem")
+		t.Setenv("HERMES_TLS_SERVER_NAME", "hermes.internal")
+
+		cfg := config.MustLoad()
+
+		assert.Equal(t, config.HermesTLSConfig{
+			CAFile:     "/etc/hermes/ca.pem",
+			CertFile:   "/etc/hermes/client.pem",
+			KeyFile:    "/etc/hermes/client-key.pem",
+			ServerName: "hermes.internal",
+		}, cfg.HermesTLS)
+	})
+
+	t.Run("insecure when explicitly allowed", func(t *testing.T) {
+		t.Setenv("HERMES_INSECURE", "true")
+
+		cfg := config.MustLoad()
+
+		assert.True(t, cfg.HermesTLS.Insecure)
+	})
+
+	t.Run("client certificate without key", func(t *testing.T) {
+		t.Setenv("HERMES_TLS_CERT_FILE", "/etc/hermes/client.pem")
+
+		assert.PanicsWithValue(t,
+			"invalid configuration: HERMES_TLS_CERT_FILE and HERMES_TLS_KEY_FILE must be set together",
+			func() { config.MustLoad() })
+	})
+}
+
+func TestMustLoad_HermesCallTimeout(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("custom value", func(t *testing.T) {
+		t.Setenv("HERMES_CALL_TIMEOUT", "45s")
+
+		cfg := config.MustLoad()
+
+		assert.Equal(t, 45*time.Second, cfg.HermesCallTimeout)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		t.Setenv("HERMES_CALL_TIMEOUT", "soon")
+
+		assert.PanicsWithValue(t, "failed to parse HERMES_CALL_TIMEOUT from configuration", func() {
+			config.MustLoad()
+		})
+	})
+}
+
+func TestMustLoad_PlaceholderEmailDomain(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("random emails by default", func(t *testing.T) {
+		cfg := config.MustLoad()
+
+		assert.Empty(t, cfg.PlaceholderEmailDomain)
+	})
+
+	t.Run("custom domain", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_PLACEHOLDER_EMAIL_DOMAIN", "noreply.example.com")
+
+		cfg := config.MustLoad()
+
+		assert.Equal(t, "noreply.example.com", cfg.PlaceholderEmailDomain)
+	})
+
+	t.Run("invalid domain", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_PLACEHOLDER_EMAIL_DOMAIN", "localhost")
+
+		assert.PanicsWithValue(t,
+			"invalid configuration: HEPHAESTUS_PLACEHOLDER_EMAIL_DOMAIN 'localhost' is not a domain",
+			func() { config.MustLoad() })
+	})
+}
+
+func TestMustLoad_DeactivateRemovedMaxShare(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("disabled by default", func(t *testing.T) {
+		cfg := config.MustLoad()
+
+		assert.Zero(t, cfg.DeactivateRemovedMaxShare)
+	})
+
+	t.Run("custom value", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_DEACTIVATE_REMOVED_MAX_SHARE", "0.1")
+
+		cfg := config.MustLoad()
+
+		assert.InDelta(t, 0.1, cfg.DeactivateRemovedMaxShare, 0)
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_DEACTIVATE_REMOVED_MAX_SHARE", "1.5")
+
+		assert.PanicsWithValue(t,
+			"invalid configuration: HEPHAESTUS_DEACTIVATE_REMOVED_MAX_SHARE must be between 0 and 1, got 1.5",
+			func() { config.MustLoad() })
+	})
+}
+
+func TestMustLoad_TaskDeadLetterAfter(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("disabled by default", func(t *testing.T) {
+		cfg := config.MustLoad()
+
+		assert.Zero(t, cfg.TaskDeadLetterAfter)
+	})
+
+	t.Run("custom value", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_TASK_DEAD_LETTER_AFTER", "5")
+
+		cfg := config.MustLoad()
+
+		assert.Equal(t, 5, cfg.TaskDeadLetterAfter)
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_TASK_DEAD_LETTER_AFTER", "-1")
+
+		assert.PanicsWithValue(t,
+			"invalid configuration: HEPHAESTUS_TASK_DEAD_LETTER_AFTER must not be negative, got -1",
+			func() { config.MustLoad() })
+	})
+}
+
+func TestMustLoad_CatchupWorkers(t *testing.T) {
+	t.Setenv("DB_HOST", "testHost")
+	t.Setenv("DB_PORT", "12345")
+	t.Setenv("DB_USERNAME", "admin")
+	t.Setenv("DB_NAME", "testName")
+	t.Setenv("HERMES_ADDRESS", "testAddr")
+
+	t.Run("sequential by default", func(t *testing.T) {
+		cfg := config.MustLoad()
+
+		assert.Zero(t, cfg.CatchupWorkers)
+	})
+
+	t.Run("custom value", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_CATCHUP_WORKERS", "4")
+
+		cfg := config.MustLoad()
+
+		assert.Equal(t, 4, cfg.CatchupWorkers)
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		t.Setenv("HEPHAESTUS_CATCHUP_WORKERS", "-2")
+
+		assert.PanicsWithValue(t,
+			"invalid configuration: HEPHAESTUS_CATCHUP_WORKERS must not be negative, got -2",
+			func() { config.MustLoad() })
+	})
+}
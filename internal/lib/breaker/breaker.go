@@ -0,0 +1,108 @@
+package breaker
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// State is the state of a circuit breaker. Its numeric value is what the state gauge reports.
+type State int
+
+const (
+	// Closed lets every call through.
+	Closed State = iota
+	// HalfOpen lets a single trial run through after a successful probe.
+	HalfOpen
+	// Open blocks calls until a probe succeeds.
+	Open
+)
+
+// String returns the lower-case name of the state.
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case HalfOpen:
+		return "half_open"
+	case Open:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// Breaker counts consecutive failures and opens once threshold is reached. An open breaker
+// is moved to half-open by a successful probe; the next result then closes or re-opens it.
+type Breaker struct {
+	mu        sync.Mutex
+	threshold int
+	failures  int
+	state     State
+	gauge     prometheus.Gauge
+}
+
+// New creates a closed Breaker that opens after threshold consecutive failures.
+// gauge, if not nil, is set to the current state on creation and on every transition.
+func New(threshold int, gauge prometheus.Gauge) *Breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	b := &Breaker{threshold: threshold, gauge: gauge}
+	if gauge != nil {
+		gauge.Set(float64(Closed))
+	}
+
+	return b
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Success records a successful call and closes the breaker.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.setState(Closed)
+}
+
+// Failure records a failed call. A half-open breaker re-opens immediately, a closed one
+// opens once the number of consecutive failures reaches the threshold.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == HalfOpen || b.failures >= b.threshold {
+		b.setState(Open)
+	}
+}
+
+// ProbeSucceeded moves an open breaker to half-open, allowing a trial call.
+func (b *Breaker) ProbeSucceeded() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == Open {
+		b.setState(HalfOpen)
+	}
+}
+
+func (b *Breaker) setState(state State) {
+	if b.state == state {
+		return
+	}
+
+	b.state = state
+	if b.gauge != nil {
+		b.gauge.Set(float64(state))
+	}
+}
@@ -0,0 +1,74 @@
+package breaker_test
+
+import (
+	"testing"
+
+	"github.com/UnknownOlympus/hephaestus/internal/lib/breaker"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker(t *testing.T) {
+	t.Run("opens after threshold consecutive failures", func(t *testing.T) {
+		b := breaker.New(3, nil)
+
+		b.Failure()
+		b.Failure()
+		require.Equal(t, breaker.Closed, b.State())
+
+		b.Failure()
+		require.Equal(t, breaker.Open, b.State())
+	})
+
+	t.Run("success resets the failure count", func(t *testing.T) {
+		b := breaker.New(2, nil)
+
+		b.Failure()
+		b.Success()
+		b.Failure()
+
+		require.Equal(t, breaker.Closed, b.State())
+	})
+
+	t.Run("half-open closes on success and re-opens on failure", func(t *testing.T) {
+		b := breaker.New(1, nil)
+
+		b.Failure()
+		b.ProbeSucceeded()
+		require.Equal(t, breaker.HalfOpen, b.State())
+		b.Failure()
+		require.Equal(t, breaker.Open, b.State())
+
+		b.ProbeSucceeded()
+		b.Success()
+		require.Equal(t, breaker.Closed, b.State())
+	})
+
+	t.Run("gauge follows a full open, half-open, closed cycle", func(t *testing.T) {
+		gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_circuit_state"}, []string{"breaker", "service"})
+		b := breaker.New(2, gauge.WithLabelValues("db", "task"))
+		state := func() float64 { return testutil.ToFloat64(gauge.WithLabelValues("db", "task")) }
+
+		require.InDelta(t, 0, state(), 0)
+
+		b.Failure()
+		require.InDelta(t, 0, state(), 0)
+		b.Failure()
+		require.InDelta(t, 2, state(), 0)
+
+		b.ProbeSucceeded()
+		require.InDelta(t, 1, state(), 0)
+
+		b.Success()
+		require.InDelta(t, 0, state(), 0)
+	})
+
+	t.Run("probe does nothing on a closed breaker", func(t *testing.T) {
+		b := breaker.New(1, nil)
+
+		b.ProbeSucceeded()
+
+		require.Equal(t, breaker.Closed, b.State())
+	})
+}
@@ -0,0 +1,45 @@
+package errtrack
+
+import "sync"
+
+// Tracker deduplicates repeated identical errors. It remembers the signature of the last
+// observed error and how many times in a row it occurred.
+type Tracker struct {
+	mu            sync.Mutex
+	logEvery      int
+	lastSignature string
+	count         int
+}
+
+// New creates a Tracker that reports every logEvery-th repetition of the same error.
+// A non-positive logEvery means that only changes of the error are reported.
+func New(logEvery int) *Tracker {
+	return &Tracker{logEvery: logEvery}
+}
+
+// Observe records the error and returns how many times in a row it has occurred,
+// and whether it should be reported: on the first occurrence, when the error changed,
+// or on every logEvery-th repetition.
+func (t *Tracker) Observe(err error) (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	signature := err.Error()
+	if signature != t.lastSignature {
+		t.lastSignature = signature
+		t.count = 1
+		return t.count, true
+	}
+
+	t.count++
+	return t.count, t.logEvery > 0 && t.count%t.logEvery == 0
+}
+
+// Reset forgets the last observed error, e.g. after a successful run.
+func (t *Tracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastSignature = ""
+	t.count = 0
+}
@@ -0,0 +1,70 @@
+package errtrack_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UnknownOlympus/hephaestus/internal/lib/errtrack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker_RepeatedErrors(t *testing.T) {
+	t.Parallel()
+
+	tracker := errtrack.New(3)
+	err := errors.New("schema mismatch")
+
+	expected := []struct {
+		count  int
+		report bool
+	}{
+		{1, true},
+		{2, false},
+		{3, true},
+		{4, false},
+		{5, false},
+		{6, true},
+	}
+
+	for _, want := range expected {
+		count, report := tracker.Observe(err)
+		assert.Equal(t, want.count, count)
+		assert.Equal(t, want.report, report)
+	}
+}
+
+func TestTracker_ChangingErrors(t *testing.T) {
+	t.Parallel()
+
+	tracker := errtrack.New(3)
+
+	count, report := tracker.Observe(errors.New("first"))
+	assert.Equal(t, 1, count)
+	assert.True(t, report)
+
+	count, report = tracker.Observe(errors.New("first"))
+	assert.Equal(t, 2, count)
+	assert.False(t, report)
+
+	count, report = tracker.Observe(errors.New("second"))
+	assert.Equal(t, 1, count)
+	assert.True(t, report)
+}
+
+func TestTracker_Reset(t *testing.T) {
+	t.Parallel()
+
+	tracker := errtrack.New(0)
+	err := errors.New("boom")
+
+	tracker.Observe(err)
+	count, report := tracker.Observe(err)
+	assert.Equal(t, 2, count)
+	assert.False(t, report)
+
+	tracker.Reset()
+
+	count, report = tracker.Observe(err)
+	assert.Equal(t, 1, count)
+	assert.True(t, report)
+}
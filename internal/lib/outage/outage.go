@@ -0,0 +1,102 @@
+package outage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/lib/breaker"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrPaused is returned by Allow while a confirmed outage keeps the services paused
+// and the connectivity probe failed.
+var ErrPaused = errors.New("dependency circuit is open")
+
+// Pinger probes the connectivity of the shared dependency.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Coordinator tracks the health of a dependency shared by several services. Every service
+// reports its results to the same Coordinator, so a confirmed outage pauses all of them at once
+// and they resume together after recovery. The outage and the recovery are logged only once,
+// by the Coordinator, instead of by every service.
+type Coordinator struct {
+	log     *slog.Logger
+	name    string
+	pinger  Pinger
+	breaker *breaker.Breaker
+
+	// mu serializes state transitions so that each one is logged exactly once.
+	mu       sync.Mutex
+	pausedAt time.Time
+}
+
+// New creates a Coordinator for the dependency called name. The services are paused after
+// threshold consecutive failures and every paused run first probes connectivity through pinger.
+// gauge, if not nil, reports the breaker state.
+func New(log *slog.Logger, name string, threshold int, pinger Pinger, gauge prometheus.Gauge) *Coordinator {
+	return &Coordinator{
+		log:     log.With(slog.String("dependency", name)),
+		name:    name,
+		pinger:  pinger,
+		breaker: breaker.New(threshold, gauge),
+	}
+}
+
+// State returns the state of the underlying breaker.
+func (c *Coordinator) State() breaker.State {
+	return c.breaker.State()
+}
+
+// Paused reports whether a confirmed outage is in progress.
+func (c *Coordinator) Paused() bool {
+	return c.breaker.State() != breaker.Closed
+}
+
+// Allow decides whether a run may use the dependency. While paused it probes connectivity:
+// a successful probe lets the run through as a trial, a failed one returns ErrPaused.
+func (c *Coordinator) Allow(ctx context.Context) error {
+	if c.breaker.State() != breaker.Open {
+		return nil
+	}
+
+	if err := c.pinger.Ping(ctx); err != nil {
+		return fmt.Errorf("%w: %s is unreachable: %w", ErrPaused, c.name, err)
+	}
+
+	c.breaker.ProbeSucceeded()
+	return nil
+}
+
+// Success records a run that used the dependency successfully and ends a pause, if any.
+func (c *Coordinator) Success() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	paused := c.breaker.State() != breaker.Closed
+	c.breaker.Success()
+	if paused {
+		c.log.Warn("Dependency recovered, resuming all services",
+			"outage_duration", time.Since(c.pausedAt).Round(time.Second).String())
+		c.pausedAt = time.Time{}
+	}
+}
+
+// Failure records a run that failed on the dependency. The failure that confirms an outage
+// pauses all services and raises a single alert.
+func (c *Coordinator) Failure(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	before := c.breaker.State()
+	c.breaker.Failure()
+	if before == breaker.Closed && c.breaker.State() == breaker.Open {
+		c.pausedAt = time.Now()
+		c.log.Error("Dependency outage confirmed, pausing all services", "error", err)
+	}
+}
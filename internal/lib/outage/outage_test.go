@@ -0,0 +1,84 @@
+package outage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/UnknownOlympus/hephaestus/internal/lib/breaker"
+	"github.com/UnknownOlympus/hephaestus/internal/lib/outage"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePinger struct {
+	err   error
+	calls int
+}
+
+func (p *fakePinger) Ping(_ context.Context) error {
+	p.calls++
+	return p.err
+}
+
+func TestCoordinator(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_circuit_state"})
+	pinger := &fakePinger{}
+	coordinator := outage.New(logger, "database", 2, pinger, gauge)
+	dbErr := errors.New("connection refused")
+
+	t.Run("does not probe while closed", func(t *testing.T) {
+		require.NoError(t, coordinator.Allow(t.Context()))
+		require.Zero(t, pinger.calls)
+		require.False(t, coordinator.Paused())
+	})
+
+	t.Run("failures from several services confirm a single outage", func(t *testing.T) {
+		coordinator.Failure(dbErr)
+		require.False(t, coordinator.Paused())
+		coordinator.Failure(dbErr)
+		coordinator.Failure(dbErr)
+
+		require.True(t, coordinator.Paused())
+		require.Equal(t, breaker.Open, coordinator.State())
+		require.InDelta(t, float64(breaker.Open), testutil.ToFloat64(gauge), 0)
+		require.Equal(t, 1, strings.Count(logs.String(), "Dependency outage confirmed"))
+	})
+
+	t.Run("stays paused while the probe fails", func(t *testing.T) {
+		pinger.err = dbErr
+
+		err := coordinator.Allow(t.Context())
+
+		require.ErrorIs(t, err, outage.ErrPaused)
+		require.ErrorIs(t, err, dbErr)
+		require.Equal(t, 1, pinger.calls)
+	})
+
+	t.Run("a failed trial does not raise a new alert", func(t *testing.T) {
+		pinger.err = nil
+		require.NoError(t, coordinator.Allow(t.Context()))
+		require.Equal(t, breaker.HalfOpen, coordinator.State())
+
+		coordinator.Failure(dbErr)
+
+		require.Equal(t, breaker.Open, coordinator.State())
+		require.Equal(t, 1, strings.Count(logs.String(), "Dependency outage confirmed"))
+	})
+
+	t.Run("resumes once after a successful trial", func(t *testing.T) {
+		require.NoError(t, coordinator.Allow(t.Context()))
+		coordinator.Success()
+		coordinator.Success()
+
+		require.False(t, coordinator.Paused())
+		require.InDelta(t, float64(breaker.Closed), testutil.ToFloat64(gauge), 0)
+		require.Equal(t, 1, strings.Count(logs.String(), "Dependency recovered"))
+	})
+}
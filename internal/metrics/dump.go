@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+)
+
+// DumpMetrics gathers the current values of all metrics from the gatherer and writes them
+// to the file at path in the Prometheus text exposition format. It is meant to be called on
+// graceful shutdown, so that short-lived runs keep their final metrics even if they were never scraped.
+func DumpMetrics(gatherer prometheus.Gatherer, path string) error {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, family := range families {
+		if _, err = expfmt.MetricFamilyToText(&buf, family); err != nil {
+			return fmt.Errorf("failed to encode metric family '%s': %w", family.GetName(), err)
+		}
+	}
+
+	filePerm := 0o600
+	if err = os.WriteFile(filepath.Clean(path), buf.Bytes(), os.FileMode(filePerm)); err != nil {
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package metrics_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/UnknownOlympus/hephaestus/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpMetrics(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	appMetrics := metrics.NewMetrics(reg)
+	appMetrics.Runs.WithLabelValues("success").Inc()
+	appMetrics.EmailsFixed.Add(2)
+
+	path := filepath.Join(t.TempDir(), "metrics.prom")
+
+	err := metrics.DumpMetrics(reg, path)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `hephaestus_runs_total{status="success"} 1`)
+	assert.Contains(t, string(content), "hephaestus_emails_fixed_total 2")
+	assert.Contains(t, string(content), "# TYPE hephaestus_runs_total counter")
+}
+
+func TestDumpMetrics_WriteError(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	_ = metrics.NewMetrics(reg)
+
+	err := metrics.DumpMetrics(reg, filepath.Join(t.TempDir(), "missing", "metrics.prom"))
+
+	require.ErrorContains(t, err, "failed to write metrics snapshot")
+}
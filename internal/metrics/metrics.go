@@ -6,28 +6,62 @@ import (
 )
 
 // Metrics holds the various metrics used for monitoring the application.
-// It includes counters for runs, login attempts, and items parsed,
-// a gauge for the last successful run, and a histogram for run duration.
+// It includes counters for runs and items parsed, a gauge for the last
+// successful run, and a histogram for run duration.
 type Metrics struct {
 	Runs              *prometheus.CounterVec
 	ItemsParsed       *prometheus.CounterVec
 	LastSuccessfulRun *prometheus.GaugeVec
 	RunDuration       *prometheus.HistogramVec
 	EmailsFixed       prometheus.Counter
+	PhonesFixed       prometheus.Counter
 	DBQueryDuration   *prometheus.HistogramVec
+	RepeatedErrors    *prometheus.GaugeVec
+	SyncResults       *prometheus.CounterVec
+	CircuitState      *prometheus.GaugeVec
+	PoolExhausted     prometheus.Counter
+	Quarantined       *prometheus.GaugeVec
+	FilteredItems     *prometheus.CounterVec
+
+	OldestQuarantinedAge  *prometheus.GaugeVec
+	HermesRequests        *prometheus.CounterVec
+	HermesRequestDuration *prometheus.HistogramVec
+	HermesErrors          *prometheus.CounterVec
+	DataUnchangedSeconds  *prometheus.GaugeVec
+	TasksProcessed        *prometheus.CounterVec
+	EmployeesFailed       prometheus.Counter
+}
+
+// Buckets configures the histogram buckets of the duration metrics.
+// A nil field keeps the default buckets for that histogram.
+type Buckets struct {
+	Run   []float64 // buckets for hephaestus_run_duration_seconds
+	Query []float64 // buckets for hephaestus_db_query_duration_seconds
 }
 
 // NewMetrics creates a new Metrics instance with the provided Registerer.
-// It initializes various Prometheus metrics including counters for runs,
-// login attempts, and items parsed, as well as gauges and histograms for
-// tracking the last successful run and the duration of runs.
+// It initializes various Prometheus metrics including counters for runs
+// and items parsed, as well as gauges and histograms for tracking the last
+// successful run and the duration of runs.
 //
 // Parameters:
 //   - reg: A prometheus.Registerer used to register the metrics.
+//   - buckets: Optional histogram buckets; only the first value is used.
 //
 // Returns:
 //   - A pointer to the newly created Metrics instance.
-func NewMetrics(reg prometheus.Registerer) *Metrics {
+func NewMetrics(reg prometheus.Registerer, buckets ...Buckets) *Metrics {
+	runBuckets := prometheus.DefBuckets
+	queryBuckets := prometheus.DefBuckets
+	if len(buckets) > 0 {
+		if buckets[0].Run != nil {
+			runBuckets = buckets[0].Run
+		}
+		if buckets[0].Query != nil {
+			queryBuckets = buckets[0].Query
+		}
+	}
+
 	metrics := &Metrics{
 		Runs: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
 			Name: "hephaestus_runs_total",
@@ -42,18 +76,76 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			Help: "Last time when run was successfully",
 		}, []string{"type"}),
 		RunDuration: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
-			Name: "hephaestus_run_duration_seconds",
-			Help: "Measures how long it takes for a full parser cycle to complete",
+			Name:    "hephaestus_run_duration_seconds",
+			Help:    "Measures how long it takes for a full parser cycle to complete",
+			Buckets: runBuckets,
 		}, []string{"type"}),
 		EmailsFixed: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "hephaestus_emails_fixed_total",
 			Help: "Total number of employee emails that were fixed or generated.",
 		}),
+		PhonesFixed: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "hephaestus_phones_fixed_total",
+			Help: "Total number of invalid employee phone numbers that were normalized or removed.",
+		}),
 		DBQueryDuration: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
 			Name:    "hephaestus_db_query_duration_seconds",
 			Help:    "Duration of database queries.",
-			Buckets: prometheus.DefBuckets,
+			Buckets: queryBuckets,
 		}, []string{"query_type"}), // query_type: 'get_employee', 'upsert_task'
+		RepeatedErrors: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hephaestus_repeated_error",
+			Help: "Number of consecutive runs that failed with the same error, 0 after a successful run.",
+		}, []string{"type"}),
+		SyncResults: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "hephaestus_sync_results_total",
+			Help: "Outcome of Hermes responses: new data, no data upstream, or unchanged because hashes match.",
+		}, []string{"type", "result"}), // result: 'new_data', 'no_data', 'unchanged'
+		CircuitState: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hephaestus_circuit_state",
+			Help: "State of a circuit breaker: 0 closed, 1 half-open, 2 open.",
+		}, []string{"breaker", "service"}), // breaker: 'db'; service: 'task' or 'shared'
+		PoolExhausted: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "hephaestus_pool_exhausted_total",
+			Help: "Total number of queries that timed out waiting for a free database connection.",
+		}),
+		Quarantined: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hephaestus_quarantined_items",
+			Help: "Number of items that failed to save in the last run without failing it.",
+		}, []string{"type"}),
+		FilteredItems: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "hephaestus_filtered_items_total",
+			Help: "Total number of received items that were skipped by an ingestion filter.",
+		}, []string{"type", "reason"}), // reason: 'open'
+		OldestQuarantinedAge: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hephaestus_oldest_quarantined_age_seconds",
+			Help: "Age of the oldest quarantined item, 0 when nothing is quarantined.",
+		}, []string{"type"}),
+		HermesRequests: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "hephaestus_hermes_requests_total",
+			Help: "Total number of gRPC calls made to Hermes.",
+		}, []string{"method"}), // method: 'GetEmployees', 'GetDailyTasks', 'GetTaskTypes'
+		HermesRequestDuration: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "hephaestus_hermes_request_duration_seconds",
+			Help:    "Duration of gRPC calls made to Hermes, including retries.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+		HermesErrors: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "hephaestus_hermes_errors_total",
+			Help: "Total number of gRPC calls to Hermes that returned an error.",
+		}, []string{"method", "code"}), // code: gRPC status code, e.g. 'Unavailable'
+		DataUnchangedSeconds: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hephaestus_data_unchanged_seconds",
+			Help: "Time since Hermes last returned changed data, updated on every cycle with matching hashes.",
+		}, []string{"type"}),
+		TasksProcessed: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "hephaestus_tasks_processed_total",
+			Help: "Total number of tasks received from Hermes by outcome.",
+		}, []string{"outcome"}), // outcome: 'saved', 'skipped', 'failed', 'dead_lettered'
+		EmployeesFailed: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "hephaestus_employees_failed_total",
+			Help: "Total number of employees that failed to save or update.",
+		}),
 	}
 
 	metrics.Runs.WithLabelValues("success")
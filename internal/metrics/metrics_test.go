@@ -5,6 +5,9 @@ import (
 
 	"github.com/UnknownOlympus/hephaestus/internal/metrics"
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewMetrics(_ *testing.T) {
@@ -12,3 +15,52 @@ func TestNewMetrics(_ *testing.T) {
 
 	_ = metrics.NewMetrics(reg)
 }
+
+func TestNewMetrics_CustomBuckets(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	m := metrics.NewMetrics(reg, metrics.Buckets{
+		Run:   []float64{1, 10, 60},
+		Query: []float64{0.01, 0.1},
+	})
+	m.RunDuration.WithLabelValues("task").Observe(5)
+	m.DBQueryDuration.WithLabelValues("get_employee").Observe(0.05)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{1, 10, 60}, bucketBounds(t, families, "hephaestus_run_duration_seconds"))
+	assert.Equal(t, []float64{0.01, 0.1}, bucketBounds(t, families, "hephaestus_db_query_duration_seconds"))
+}
+
+func TestNewMetrics_DefaultBuckets(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	m := metrics.NewMetrics(reg)
+	m.RunDuration.WithLabelValues("task").Observe(5)
+	m.DBQueryDuration.WithLabelValues("get_employee").Observe(0.05)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	assert.Equal(t, prometheus.DefBuckets, bucketBounds(t, families, "hephaestus_run_duration_seconds"))
+	assert.Equal(t, prometheus.DefBuckets, bucketBounds(t, families, "hephaestus_db_query_duration_seconds"))
+}
+
+func bucketBounds(t *testing.T, families []*dto.MetricFamily, name string) []float64 {
+	t.Helper()
+
+	for _, mf := range families {
+		if mf.GetName() != name {
+			continue
+		}
+		require.NotEmpty(t, mf.GetMetric())
+		var bounds []float64
+		for _, b := range mf.GetMetric()[0].GetHistogram().GetBucket() {
+			bounds = append(bounds, b.GetUpperBound())
+		}
+		return bounds
+	}
+	t.Fatalf("metric family %s not gathered", name)
+	return nil
+}
@@ -1,6 +1,12 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultDateLayout is the layout of the dates in a TaskDTO when no other layout is configured.
+const DefaultDateLayout = "02.01.2006"
 
 type Task struct {
 	ID            int       `json:"id"`
@@ -15,3 +21,91 @@ type Task struct {
 	Executors     []string  `json:"executors"`
 	IsClosed      bool      `json:"is_closed"`
 }
+
+// FailedTask is a task that repeatedly failed to save and was moved to the dead-letter queue.
+type FailedTask struct {
+	Task     Task
+	Attempts int
+	Error    string
+	FailedAt time.Time
+}
+
+// TaskDTO is the export representation of a Task. Its dates are formatted with a configurable
+// layout and an unset closing date is omitted. Task itself keeps the default RFC 3339 encoding,
+// which preserves the full timestamps.
+type TaskDTO struct {
+	ID            int      `json:"id"`
+	Type          string   `json:"type"`
+	CreatedAt     string   `json:"createdAt"`
+	ClosedAt      string   `json:"closedAt,omitempty"`
+	Description   string   `json:"description"`
+	Address       string   `json:"address"`
+	CustomerName  string   `json:"customerName"`
+	CustomerLogin string   `json:"customerLogin"`
+	Comments      []string `json:"comments"`
+	Executors     []string `json:"executors"`
+	IsClosed      bool     `json:"is_closed"`
+}
+
+// NewTaskDTO converts task for export, formatting its dates with layout or DefaultDateLayout if
+// layout is empty. A zero closing date, or the Unix epoch used for tasks Hermes sends without one,
+// is left out.
+func NewTaskDTO(task Task, layout string) TaskDTO {
+	if layout == "" {
+		layout = DefaultDateLayout
+	}
+
+	dto := TaskDTO{
+		ID:            task.ID,
+		Type:          task.Type,
+		Description:   task.Description,
+		Address:       task.Address,
+		CustomerName:  task.CustomerName,
+		CustomerLogin: task.CustomerLogin,
+		Comments:      task.Comments,
+		Executors:     task.Executors,
+		IsClosed:      task.IsClosed,
+	}
+	if !task.CreatedAt.IsZero() {
+		dto.CreatedAt = task.CreatedAt.Format(layout)
+	}
+	if !task.ClosedAt.IsZero() && !task.ClosedAt.Equal(time.Unix(0, 0)) {
+		dto.ClosedAt = task.ClosedAt.Format(layout)
+	}
+
+	return dto
+}
+
+// Task converts the DTO back, parsing its dates with layout or DefaultDateLayout if layout is empty.
+// Dates keep only the precision of the layout, and an omitted closing date becomes the zero time.
+func (d TaskDTO) Task(layout string) (Task, error) {
+	if layout == "" {
+		layout = DefaultDateLayout
+	}
+
+	task := Task{
+		ID:            d.ID,
+		Type:          d.Type,
+		Description:   d.Description,
+		Address:       d.Address,
+		CustomerName:  d.CustomerName,
+		CustomerLogin: d.CustomerLogin,
+		Comments:      d.Comments,
+		Executors:     d.Executors,
+		IsClosed:      d.IsClosed,
+	}
+
+	var err error
+	if d.CreatedAt != "" {
+		if task.CreatedAt, err = time.Parse(layout, d.CreatedAt); err != nil {
+			return Task{}, fmt.Errorf("failed to parse creation date of task '%d': %w", d.ID, err)
+		}
+	}
+	if d.ClosedAt != "" {
+		if task.ClosedAt, err = time.Parse(layout, d.ClosedAt); err != nil {
+			return Task{}, fmt.Errorf("failed to parse closing date of task '%d': %w", d.ID, err)
+		}
+	}
+
+	return task, nil
+}
@@ -0,0 +1,90 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskDTO_JSON(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		task     models.Task
+		layout   string
+		expected string
+	}{
+		{
+			name: "closed task",
+			task: models.Task{
+				ID: 1, Type: "Repair", CreatedAt: created, ClosedAt: created.AddDate(0, 0, 2),
+				Comments: []string{"done"}, Executors: []string{"Doe J."}, IsClosed: true,
+			},
+			expected: `{"id":1,"type":"Repair","createdAt":"01.03.2024","closedAt":"03.03.2024",` +
+				`"description":"","address":"","customerName":"","customerLogin":"",` +
+				`"comments":["done"],"executors":["Doe J."],"is_closed":true}`,
+		},
+		{
+			name: "open task omits the closing date",
+			task: models.Task{ID: 2, Type: "Install", CreatedAt: created, ClosedAt: time.Unix(0, 0)},
+			expected: `{"id":2,"type":"Install","createdAt":"01.03.2024",` +
+				`"description":"","address":"","customerName":"","customerLogin":"",` +
+				`"comments":null,"executors":null,"is_closed":false}`,
+		},
+		{
+			name:   "custom layout",
+			task:   models.Task{ID: 3, CreatedAt: created},
+			layout: time.DateOnly,
+			expected: `{"id":3,"type":"","createdAt":"2024-03-01",` +
+				`"description":"","address":"","customerName":"","customerLogin":"",` +
+				`"comments":null,"executors":null,"is_closed":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := json.Marshal(models.NewTaskDTO(tt.task, tt.layout))
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(data))
+		})
+	}
+}
+
+func TestTaskDTO_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	task := models.Task{
+		ID: 1, Type: "Repair", Description: "broken cable",
+		CreatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		ClosedAt:  time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+		Executors: []string{"Doe J."}, IsClosed: true,
+	}
+
+	data, err := json.Marshal(models.NewTaskDTO(task, ""))
+	require.NoError(t, err)
+
+	var dto models.TaskDTO
+	require.NoError(t, json.Unmarshal(data, &dto))
+	got, err := dto.Task("")
+
+	require.NoError(t, err)
+	assert.Equal(t, task, got)
+
+	t.Run("invalid date", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := models.TaskDTO{ID: 5, CreatedAt: "2024-03-01"}.Task("")
+
+		require.ErrorContains(t, err, "failed to parse creation date of task '5'")
+	})
+}
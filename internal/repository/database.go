@@ -15,16 +15,51 @@ type Database interface {
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
 }
 
-// NewDatabase creates a new PostgreSQL database connection pool using the provided host, port, username, password, and database name.
-func NewDatabase(host, port, username, password, dbName string) (*pgxpool.Pool, error) {
-	var (
-		ctxTimeout = 5 * time.Second
-		idleTime   = 30 * time.Second
-		hcPeriod   = 30 * time.Second
-	)
-	var err error
+// Default connection pool settings, used when PoolOptions leaves a value unset.
+const (
+	DefaultMaxConns        int32 = 10
+	DefaultMinConns        int32 = 3
+	DefaultMaxConnIdleTime       = 30 * time.Second
+)
+
+// PoolOptions holds the connection pool tuning knobs. Zero values fall back to the defaults.
+type PoolOptions struct {
+	MaxConns        int32         // MaxConns is the maximum size of the pool.
+	MinConns        int32         // MinConns is the minimum number of connections kept open.
+	MaxConnIdleTime time.Duration // MaxConnIdleTime is the duration after which an idle connection is closed.
+}
+
+// NewDatabase creates a new PostgreSQL database connection pool using the provided host, port, username, password,
+// database name and pool options.
+func NewDatabase(host, port, username, password, dbName string, opts PoolOptions) (*pgxpool.Pool, error) {
+	ctxTimeout := 5 * time.Second
+
+	poolConfig, err := NewPoolConfig(host, port, username, password, dbName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	defer cancel()
+
+	dbpool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create connection to PostgreSQL: %w", err)
+	}
+
+	if err = dbpool.Ping(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to ping PostgreSQL DB: %w", err)
+	}
+
+	return dbpool, nil
+}
+
+// NewPoolConfig builds the pgxpool configuration for the given connection parameters and pool options.
+func NewPoolConfig(host, port, username, password, dbName string, opts PoolOptions) (*pgxpool.Config, error) {
+	hcPeriod := 30 * time.Second
 
 	dbHost := net.JoinHostPort(host, port)
 	dbURL := fmt.Sprintf(
@@ -40,21 +75,24 @@ func NewDatabase(host, port, username, password, dbName string) (*pgxpool.Pool,
 		return nil, fmt.Errorf("failed to parse database config: %w", err)
 	}
 
-	poolConfig.MinConns = 3
-	poolConfig.MaxConnIdleTime = idleTime
-	poolConfig.HealthCheckPeriod = hcPeriod
-
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
-	defer cancel()
-
-	dbpool, err := pgxpool.NewWithConfig(ctx, poolConfig)
-	if err != nil {
-		return nil, fmt.Errorf("unable to create connection to PostgreSQL: %w", err)
+	poolConfig.MaxConns = DefaultMaxConns
+	if opts.MaxConns > 0 {
+		poolConfig.MaxConns = opts.MaxConns
+	}
+	poolConfig.MinConns = DefaultMinConns
+	if opts.MinConns > 0 {
+		poolConfig.MinConns = opts.MinConns
+	}
+	poolConfig.MaxConnIdleTime = DefaultMaxConnIdleTime
+	if opts.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = opts.MaxConnIdleTime
 	}
+	poolConfig.HealthCheckPeriod = hcPeriod
 
-	if err = dbpool.Ping(context.Background()); err != nil {
-		return nil, fmt.Errorf("failed to ping PostgreSQL DB: %w", err)
+	if poolConfig.MinConns > poolConfig.MaxConns {
+		return nil, fmt.Errorf("invalid pool options: min conns (%d) is greater than max conns (%d)",
+			poolConfig.MinConns, poolConfig.MaxConns)
 	}
 
-	return dbpool, nil
+	return poolConfig, nil
 }
@@ -51,7 +51,8 @@ func TestNewDatabase_Success(t *testing.T) {
 		t.Fatalf("failed to get mapped port: %v", err)
 	}
 
-	dbpool, err := repository.NewDatabase(host, port.Port(), "testuser", "testpassword", "testdb")
+	dbpool, err := repository.NewDatabase(host, port.Port(), "testuser", "testpassword", "testdb",
+		repository.PoolOptions{})
 	if err != nil {
 		t.Fatalf("NewDatabase failed: %v", err)
 	}
@@ -69,7 +70,7 @@ func TestNewDatabase_Success(t *testing.T) {
 
 func TestNewDatabase_ParseConfigError(t *testing.T) {
 	t.Parallel()
-	dbpool, err := repository.NewDatabase("localhost", "invalid-port", "user", "pass", "db")
+	dbpool, err := repository.NewDatabase("localhost", "invalid-port", "user", "pass", "db", repository.PoolOptions{})
 
 	require.Error(t, err, "Expected an error for invalid database URL, but got nil")
 	require.Nil(t, dbpool, "Expected nil dbpool, got: %v", dbpool)
@@ -81,7 +82,7 @@ func TestNewDatabase_ParseConfigError(t *testing.T) {
 
 func TestNewDatabase_ConnectionError(t *testing.T) {
 	t.Parallel()
-	dbpool, err := repository.NewDatabase("nonexistent-host", "5432", "user", "pass", "db")
+	dbpool, err := repository.NewDatabase("nonexistent-host", "5432", "user", "pass", "db", repository.PoolOptions{})
 
 	require.Error(t, err, "Expected an error for connection failure, but got nil")
 	if dbpool != nil {
@@ -105,3 +106,39 @@ func TestNewDatabase_ConnectionError(t *testing.T) {
 		)
 	}
 }
+
+func TestNewPoolConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("custom values", func(t *testing.T) {
+		t.Parallel()
+		opts := repository.PoolOptions{MaxConns: 40, MinConns: 8, MaxConnIdleTime: 2 * time.Minute}
+
+		poolConfig, err := repository.NewPoolConfig("localhost", "5432", "user", "pass", "db", opts)
+
+		require.NoError(t, err)
+		require.Equal(t, int32(40), poolConfig.MaxConns)
+		require.Equal(t, int32(8), poolConfig.MinConns)
+		require.Equal(t, 2*time.Minute, poolConfig.MaxConnIdleTime)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		poolConfig, err := repository.NewPoolConfig("localhost", "5432", "user", "pass", "db", repository.PoolOptions{})
+
+		require.NoError(t, err)
+		require.Equal(t, repository.DefaultMaxConns, poolConfig.MaxConns)
+		require.Equal(t, repository.DefaultMinConns, poolConfig.MinConns)
+		require.Equal(t, repository.DefaultMaxConnIdleTime, poolConfig.MaxConnIdleTime)
+	})
+
+	t.Run("min greater than max", func(t *testing.T) {
+		t.Parallel()
+		opts := repository.PoolOptions{MaxConns: 2, MinConns: 5}
+
+		_, err := repository.NewPoolConfig("localhost", "5432", "user", "pass", "db", opts)
+
+		require.ErrorContains(t, err, "invalid pool options")
+	})
+}
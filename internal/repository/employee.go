@@ -3,13 +3,17 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/UnknownOlympus/hephaestus/internal/models"
+	"github.com/jackc/pgx/v5"
 )
 
 // SaveEmployee saves an employee to the database. It inserts a new record with the provided details
-// unless an employee with the same identifier already exists.
+// unless an employee with the same identifier already exists, in which case the details are kept
+// and only a dismissed employee is marked as active again.
 func (r *Repository) SaveEmployee(
 	ctx context.Context,
 	identifier int,
@@ -21,9 +25,10 @@ func (r *Repository) SaveEmployee(
 		r.metrics.DBQueryDuration.WithLabelValues("save_employee").Observe(duration)
 	}()
 	query := `
-		INSERT INTO employees (id, fullname, shortname, position, email, phone)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (id) DO NOTHING;
+		INSERT INTO employees (id, fullname, shortname, position, email, phone, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, true)
+		ON CONFLICT (id) DO UPDATE SET is_active = true, updated_at = CURRENT_TIMESTAMP
+		WHERE NOT employees.is_active;
 	`
 
 	_, err := r.db.Exec(ctx, query, identifier, fullname, shortname, position, email, phone)
@@ -34,7 +39,8 @@ func (r *Repository) SaveEmployee(
 	return nil
 }
 
-// UpdateEmployee updates an employee's information in the database.
+// UpdateEmployee updates an employee's information in the database and marks a dismissed employee
+// as active again.
 func (r *Repository) UpdateEmployee(
 	ctx context.Context,
 	identifier int,
@@ -47,7 +53,8 @@ func (r *Repository) UpdateEmployee(
 	}()
 	query := `
 		UPDATE employees
-		SET fullname = $2, shortname = $3, position = $4, email = $5, phone = $6, updated_at = CURRENT_TIMESTAMP
+		SET fullname = $2, shortname = $3, position = $4, email = $5, phone = $6, is_active = true,
+			updated_at = CURRENT_TIMESTAMP
 		WHERE id = $1;
 	`
 
@@ -78,3 +85,166 @@ func (r *Repository) GetEmployeeByID(ctx context.Context, identifier int) (model
 
 	return result, nil
 }
+
+// GetEmployeesByPosition returns the employees holding the given position, ordered by full name.
+// It returns an empty slice when there are none.
+func (r *Repository) GetEmployeesByPosition(ctx context.Context, position string) ([]models.Employee, error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("get_employees_by_position").Observe(duration)
+	}()
+	query := `SELECT id, fullname, shortname, position, email, phone FROM employees WHERE position = $1 ORDER BY fullname`
+
+	rows, err := r.db.Query(ctx, query, position)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employees by position: %w", err)
+	}
+	defer rows.Close()
+
+	employees := make([]models.Employee, 0)
+	for rows.Next() {
+		var employee models.Employee
+		err = rows.Scan(&employee.ID, &employee.FullName, &employee.ShortName,
+			&employee.Position, &employee.Email, &employee.Phone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan employee row: %w", err)
+		}
+		employees = append(employees, employee)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate employee rows: %w", err)
+	}
+
+	return employees, nil
+}
+
+// MarkEmployeeDismissed flags the employee with the given ID as no longer active. The record is kept,
+// so GetEmployeeByID still returns it. If there is no such employee, the returned error wraps sql.ErrNoRows.
+func (r *Repository) MarkEmployeeDismissed(ctx context.Context, identifier int) error {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("mark_employee_dismissed").Observe(duration)
+	}()
+	query := `
+		UPDATE employees
+		SET is_active = false, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1;
+	`
+
+	tag, err := r.db.Exec(ctx, query, identifier)
+	if err != nil {
+		return fmt.Errorf("failed to mark employee as dismissed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("failed to mark employee %d as dismissed: %w", identifier, pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+// GetActiveEmployeeIDs returns the IDs of all employees that are not marked as dismissed, in ascending order.
+func (r *Repository) GetActiveEmployeeIDs(ctx context.Context) ([]int, error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("get_active_employee_ids").Observe(duration)
+	}()
+	query := `SELECT id FROM employees WHERE is_active ORDER BY id`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active employees: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan employee id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate employee ids: %w", err)
+	}
+
+	return ids, nil
+}
+
+// upsertEmployeesBatchSize bounds the rows of a single UpsertEmployees statement,
+// keeping it well below the PostgreSQL limit of 65535 parameters.
+const upsertEmployeesBatchSize = 1000
+
+// UpsertEmployees inserts or updates all given employees in one statement per batch of
+// upsertEmployeesBatchSize and marks dismissed employees as active again. Active rows whose data
+// did not change are left untouched. If an ID occurs more than once, the last occurrence wins.
+func (r *Repository) UpsertEmployees(ctx context.Context, employees []models.Employee) error {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("upsert_employees").Observe(duration)
+	}()
+
+	// ON CONFLICT cannot update the same row twice in one statement.
+	unique := make([]models.Employee, 0, len(employees))
+	index := make(map[int]int, len(employees))
+	for _, employee := range employees {
+		if i, ok := index[employee.ID]; ok {
+			unique[i] = employee
+			continue
+		}
+		index[employee.ID] = len(unique)
+		unique = append(unique, employee)
+	}
+
+	for start := 0; start < len(unique); start += upsertEmployeesBatchSize {
+		batch := unique[start:min(start+upsertEmployeesBatchSize, len(unique))]
+		query, args := upsertEmployeesQuery(batch)
+		if _, err := r.db.Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to upsert %d employees: %w", len(batch), err)
+		}
+	}
+
+	return nil
+}
+
+// upsertEmployeesQuery builds a multi-row upsert of employees and its arguments,
+// six per employee in column order.
+func upsertEmployeesQuery(employees []models.Employee) (string, []any) {
+	const columns = 6
+
+	values := make([]string, 0, len(employees))
+	args := make([]any, 0, len(employees)*columns)
+	for i, employee := range employees {
+		placeholders := make([]string, columns)
+		for j := range placeholders {
+			placeholders[j] = "$" + strconv.Itoa(i*columns+j+1)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+		args = append(args, employee.ID, employee.FullName, employee.ShortName,
+			employee.Position, employee.Email, employee.Phone)
+	}
+
+	query := `
+		INSERT INTO employees (id, fullname, shortname, position, email, phone)
+		VALUES ` + strings.Join(values, ", ") + `
+		ON CONFLICT (id) DO UPDATE SET
+			fullname = EXCLUDED.fullname,
+			shortname = EXCLUDED.shortname,
+			position = EXCLUDED.position,
+			email = EXCLUDED.email,
+			phone = EXCLUDED.phone,
+			is_active = true,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE NOT employees.is_active
+			OR (employees.fullname, employees.shortname, employees.position, employees.email, employees.phone)
+			IS DISTINCT FROM (EXCLUDED.fullname, EXCLUDED.shortname, EXCLUDED.position, EXCLUDED.email, EXCLUDED.phone);
+	`
+
+	return query, args
+}
@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"database/sql"
 	"regexp"
 	"testing"
 
@@ -12,16 +13,23 @@ import (
 )
 
 const saveEmployeeQuery = `
-	INSERT INTO employees (id, fullname, shortname, position, email, phone)
-	VALUES ($1, $2, $3, $4, $5, $6)
-	ON CONFLICT (id) DO NOTHING;
-`
+		INSERT INTO employees (id, fullname, shortname, position, email, phone, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, true)
+		ON CONFLICT (id) DO UPDATE SET is_active = true, updated_at = CURRENT_TIMESTAMP
+		WHERE NOT employees.is_active;
+	`
 
 const updateEmployeeQuery = `
-	UPDATE employees
-	SET fullname = $2, shortname = $3, position = $4, email = $5, phone = $6, updated_at = CURRENT_TIMESTAMP
-	WHERE id = $1;
-`
+		UPDATE employees
+		SET fullname = $2, shortname = $3, position = $4, email = $5, phone = $6, is_active = true,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1;
+	`
+const markEmployeeDismissedQuery = `
+		UPDATE employees
+		SET is_active = false, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1;
+	`
 const getEmployeeByIDQuery = `SELECT id, fullname, shortname, position, email, phone FROM employees WHERE id=$1`
 
 func TestSaveEmployee_QueryError(t *testing.T) {
@@ -235,3 +243,222 @@ func TestGetEmployeeByID_Success(t *testing.T) {
 	assert.Equal(t, expEmployee, actualEmpployee)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestMarkEmployeeDismissed(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(markEmployeeDismissedQuery)).
+			WithArgs(123).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		repo := repository.NewEmployeeRepository(mock, repoMetrics)
+		err = repo.MarkEmployeeDismissed(t.Context(), 123)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - unknown employee", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(markEmployeeDismissedQuery)).
+			WithArgs(123).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		repo := repository.NewEmployeeRepository(mock, repoMetrics)
+		err = repo.MarkEmployeeDismissed(t.Context(), 123)
+
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - query error", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(markEmployeeDismissedQuery)).
+			WithArgs(123).
+			WillReturnError(assert.AnError)
+
+		repo := repository.NewEmployeeRepository(mock, repoMetrics)
+		err = repo.MarkEmployeeDismissed(t.Context(), 123)
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpsertEmployees(t *testing.T) {
+	t.Parallel()
+
+	employees := []models.Employee{
+		{ID: 1, FullName: "John Doe", ShortName: "Doe J.", Position: "qa", Email: "j@doe.com", Phone: "+1"},
+		{ID: 2, FullName: "Jane Roe", ShortName: "Roe J.", Position: "dev", Email: "j@roe.com", Phone: "+2"},
+		{ID: 1, FullName: "John Doe", ShortName: "Doe J.", Position: "lead", Email: "j@doe.com", Phone: "+1"},
+	}
+
+	t.Run("success - single batched exec", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta("VALUES ($1, $2, $3, $4, $5, $6), ($7, $8, $9, $10, $11, $12)")+
+			`\s+ON CONFLICT \(id\) DO UPDATE SET`).
+			WithArgs(
+				1, "John Doe", "Doe J.", "lead", "j@doe.com", "+1",
+				2, "Jane Roe", "Roe J.", "dev", "j@roe.com", "+2",
+			).
+			WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+		repo := repository.NewEmployeeRepository(mock, repoMetrics)
+		err = repo.UpsertEmployees(t.Context(), employees)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success - nothing to upsert", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewEmployeeRepository(mock, repoMetrics)
+
+		require.NoError(t, repo.UpsertEmployees(t.Context(), nil))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - exec error", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		anyArgs := make([]any, 12)
+		for i := range anyArgs {
+			anyArgs[i] = pgxmock.AnyArg()
+		}
+		mock.ExpectExec("INSERT INTO employees").WithArgs(anyArgs...).WillReturnError(assert.AnError)
+
+		repo := repository.NewEmployeeRepository(mock, repoMetrics)
+		err = repo.UpsertEmployees(t.Context(), employees)
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.ErrorContains(t, err, "failed to upsert 2 employees")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetEmployeesByPosition(t *testing.T) {
+	t.Parallel()
+
+	query := regexp.QuoteMeta(
+		`SELECT id, fullname, shortname, position, email, phone FROM employees WHERE position = $1 ORDER BY fullname`)
+	columns := []string{"id", "fullname", "shortname", "position", "email", "phone"}
+
+	t.Run("success - multiple matches", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(query).
+			WithArgs("engineer").
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(2, "Jane Roe", "Roe J.", "engineer", "j@roe.com", "+2").
+				AddRow(1, "John Doe", "Doe J.", "engineer", "j@doe.com", "+1"))
+
+		repo := repository.NewEmployeeRepository(mock, repoMetrics)
+		employees, err := repo.GetEmployeesByPosition(t.Context(), "engineer")
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.Employee{
+			{ID: 2, FullName: "Jane Roe", ShortName: "Roe J.", Position: "engineer", Email: "j@roe.com", Phone: "+2"},
+			{ID: 1, FullName: "John Doe", ShortName: "Doe J.", Position: "engineer", Email: "j@doe.com", Phone: "+1"},
+		}, employees)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success - no matches", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(query).WithArgs("astronaut").WillReturnRows(pgxmock.NewRows(columns))
+
+		repo := repository.NewEmployeeRepository(mock, repoMetrics)
+		employees, err := repo.GetEmployeesByPosition(t.Context(), "astronaut")
+
+		require.NoError(t, err)
+		assert.NotNil(t, employees)
+		assert.Empty(t, employees)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - query error", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(query).WithArgs("engineer").WillReturnError(assert.AnError)
+
+		repo := repository.NewEmployeeRepository(mock, repoMetrics)
+		_, err = repo.GetEmployeesByPosition(t.Context(), "engineer")
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetActiveEmployeeIDs(t *testing.T) {
+	t.Parallel()
+
+	query := regexp.QuoteMeta(`SELECT id FROM employees WHERE is_active ORDER BY id`)
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(query).WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1).AddRow(3))
+
+		repo := repository.NewEmployeeRepository(mock, repoMetrics)
+		ids, err := repo.GetActiveEmployeeIDs(t.Context())
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 3}, ids)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - query error", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(query).WillReturnError(assert.AnError)
+
+		repo := repository.NewEmployeeRepository(mock, repoMetrics)
+		_, err = repo.GetActiveEmployeeIDs(t.Context())
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.ErrorContains(t, err, "failed to get active employees")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
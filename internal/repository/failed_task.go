@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/models"
+)
+
+// SaveFailedTask moves a task that repeatedly failed to save to the dead-letter queue, replacing
+// a previous entry for the same task.
+func (r *Repository) SaveFailedTask(ctx context.Context, task models.Task, attempts int, cause string) error {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("save_failed_task").Observe(duration)
+	}()
+
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to encode failed task '%d': %w", task.ID, err)
+	}
+
+	query := `
+		INSERT INTO failed_tasks (task_id, payload, error, attempts)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (task_id) DO UPDATE
+		SET payload = EXCLUDED.payload, error = EXCLUDED.error,
+			attempts = EXCLUDED.attempts, failed_at = CURRENT_TIMESTAMP;
+	`
+	if _, err = r.db.Exec(ctx, query, task.ID, payload, cause, attempts); err != nil {
+		return fmt.Errorf("failed to save failed task '%d': %w", task.ID, err)
+	}
+
+	return nil
+}
+
+// ListFailedTasks returns the dead-lettered tasks, oldest first.
+func (r *Repository) ListFailedTasks(ctx context.Context) ([]models.FailedTask, error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("list_failed_tasks").Observe(duration)
+	}()
+
+	query := "SELECT payload, error, attempts, failed_at FROM failed_tasks ORDER BY failed_at, task_id"
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failed tasks: %w", err)
+	}
+	defer rows.Close()
+
+	failed := make([]models.FailedTask, 0)
+	for rows.Next() {
+		var (
+			item    models.FailedTask
+			payload []byte
+		)
+		if err = rows.Scan(&payload, &item.Error, &item.Attempts, &item.FailedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan failed task row: %w", err)
+		}
+		if err = json.Unmarshal(payload, &item.Task); err != nil {
+			return nil, fmt.Errorf("failed to decode failed task: %w", err)
+		}
+		failed = append(failed, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate failed task rows: %w", err)
+	}
+
+	return failed, nil
+}
+
+// DeleteFailedTask removes a task from the dead-letter queue.
+func (r *Repository) DeleteFailedTask(ctx context.Context, taskID int) error {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("delete_failed_task").Observe(duration)
+	}()
+
+	if _, err := r.db.Exec(ctx, "DELETE FROM failed_tasks WHERE task_id = $1", taskID); err != nil {
+		return fmt.Errorf("failed to delete failed task '%d': %w", taskID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package repository_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/models"
+	"github.com/UnknownOlympus/hephaestus/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveFailedTask(t *testing.T) {
+	t.Parallel()
+
+	query := regexp.QuoteMeta("INSERT INTO failed_tasks (task_id, payload, error, attempts)")
+	task := models.Task{ID: 42, Type: "repair", Executors: []string{"Jane Doe"}}
+	payload, err := json.Marshal(task)
+	require.NoError(t, err)
+
+	t.Run("upserts the task with its error", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(query).WithArgs(42, payload, "deadlock detected", 3).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		err = repository.NewFailedTaskRepository(mock, repoMetrics).
+			SaveFailedTask(t.Context(), task, 3, "deadlock detected")
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("insert error", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(query).WithArgs(42, payload, "deadlock detected", 3).WillReturnError(assert.AnError)
+
+		err = repository.NewFailedTaskRepository(mock, repoMetrics).
+			SaveFailedTask(t.Context(), task, 3, "deadlock detected")
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestListFailedTasks(t *testing.T) {
+	t.Parallel()
+
+	query := regexp.QuoteMeta("SELECT payload, error, attempts, failed_at FROM failed_tasks")
+	columns := []string{"payload", "error", "attempts", "failed_at"}
+
+	t.Run("decodes the stored tasks", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		task := models.Task{ID: 7, Type: "install", Comments: []string{"call first"}}
+		payload, err := json.Marshal(task)
+		require.NoError(t, err)
+		failedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(query).
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(payload, "timeout", 5, failedAt))
+
+		got, err := repository.NewFailedTaskRepository(mock, repoMetrics).ListFailedTasks(t.Context())
+
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, 7, got[0].Task.ID)
+		assert.Equal(t, []string{"call first"}, got[0].Task.Comments)
+		assert.Equal(t, "timeout", got[0].Error)
+		assert.Equal(t, 5, got[0].Attempts)
+		assert.Equal(t, failedAt, got[0].FailedAt)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(query).WillReturnError(assert.AnError)
+
+		_, err = repository.NewFailedTaskRepository(mock, repoMetrics).ListFailedTasks(t.Context())
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteFailedTask(t *testing.T) {
+	t.Parallel()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM failed_tasks WHERE task_id = $1")).WithArgs(7).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	err = repository.NewFailedTaskRepository(mock, repoMetrics).DeleteFailedTask(t.Context(), 7)
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
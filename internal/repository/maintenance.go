@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultDeleteBatchSize is the number of rows DeleteTasksOlderThan removes per statement by default.
+const DefaultDeleteBatchSize = 5000
+
+// AnalyzeTables refreshes the query planner statistics of the given tables, e.g. after a bulk
+// ingestion left them stale. Without tables it does nothing.
+func (r *Repository) AnalyzeTables(ctx context.Context, tables ...string) error {
+	if len(tables) == 0 {
+		return nil
+	}
+
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("analyze_tables").Observe(duration)
+	}()
+
+	identifiers := make([]string, 0, len(tables))
+	for _, table := range tables {
+		identifiers = append(identifiers, pgx.Identifier{table}.Sanitize())
+	}
+
+	if _, err := r.db.Exec(ctx, "ANALYZE "+strings.Join(identifiers, ", ")); err != nil {
+		return fmt.Errorf("failed to analyze tables %v: %w", tables, err)
+	}
+
+	return nil
+}
+
+// DeleteTasksOlderThan deletes tasks created before cutoff together with their executor links.
+// Rows are removed in batches of at most batchSize, each in its own statement and transaction,
+// so that a large purge never holds locks long enough to block ingestion. It returns the number
+// of deleted tasks.
+func (r *Repository) DeleteTasksOlderThan(ctx context.Context, cutoff time.Time, batchSize int) (int64, error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("delete_tasks_older_than").Observe(duration)
+	}()
+
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("invalid batch size %d: must be positive", batchSize)
+	}
+
+	query := `
+		WITH batch AS (
+			SELECT task_id FROM tasks WHERE creation_date < $1 ORDER BY task_id LIMIT $2
+		), executors AS (
+			DELETE FROM task_executors WHERE task_id IN (SELECT task_id FROM batch)
+		)
+		DELETE FROM tasks WHERE task_id IN (SELECT task_id FROM batch);
+	`
+
+	var total int64
+	for {
+		tag, err := r.db.Exec(ctx, query, cutoff, batchSize)
+		if err != nil {
+			return total, fmt.Errorf("failed to delete tasks older than '%s': %w", cutoff.Format(time.DateOnly), err)
+		}
+
+		total += tag.RowsAffected()
+		if tag.RowsAffected() < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package repository_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeTables(t *testing.T) {
+	t.Parallel()
+
+	t.Run("analyzes the given tables", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(`ANALYZE "tasks", "task_executors"`)).
+			WillReturnResult(pgxmock.NewResult("ANALYZE", 0))
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+		err = repo.AnalyzeTables(t.Context(), "tasks", "task_executors")
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("quotes table names", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(`ANALYZE "tasks; DROP TABLE tasks"`)).
+			WillReturnResult(pgxmock.NewResult("ANALYZE", 0))
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+		err = repo.AnalyzeTables(t.Context(), "tasks; DROP TABLE tasks")
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("does nothing without tables", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+
+		require.NoError(t, repo.AnalyzeTables(t.Context()))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("exec error", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec("ANALYZE").WillReturnError(assert.AnError)
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+		err = repo.AnalyzeTables(t.Context(), "tasks")
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteTasksOlderThan(t *testing.T) {
+	t.Parallel()
+
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("DELETE FROM tasks WHERE task_id IN (SELECT task_id FROM batch)")
+
+	t.Run("deletes in batches until exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		for _, affected := range []int64{5000, 5000, 1200} {
+			mock.ExpectExec(query).WithArgs(cutoff, 5000).WillReturnResult(pgxmock.NewResult("DELETE", affected))
+		}
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+		deleted, err := repo.DeleteTasksOlderThan(t.Context(), cutoff, repository.DefaultDeleteBatchSize)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(11200), deleted)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stops after an exactly full last batch", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(query).WithArgs(cutoff, 2).WillReturnResult(pgxmock.NewResult("DELETE", 2))
+		mock.ExpectExec(query).WithArgs(cutoff, 2).WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+		deleted, err := repo.DeleteTasksOlderThan(t.Context(), cutoff, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("returns rows deleted before an error", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(query).WithArgs(cutoff, 10).WillReturnResult(pgxmock.NewResult("DELETE", 10))
+		mock.ExpectExec(query).WithArgs(cutoff, 10).WillReturnError(assert.AnError)
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+		deleted, err := repo.DeleteTasksOlderThan(t.Context(), cutoff, 10)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, int64(10), deleted)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rejects invalid batch size", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+		_, err = repo.DeleteTasksOlderThan(t.Context(), cutoff, 0)
+
+		require.ErrorContains(t, err, "invalid batch size")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
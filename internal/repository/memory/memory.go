@@ -0,0 +1,384 @@
+// Package memory provides a map-backed, concurrency-safe implementation of the repository
+// interfaces. It is intended for service tests that exercise real save/skip/update flows
+// without a database or per-call mock expectations.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/models"
+	"github.com/UnknownOlympus/hephaestus/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	_ repository.TaskRepoIface     = (*Store)(nil)
+	_ repository.EmployeeRepoIface = (*Store)(nil)
+	_ repository.StatusRepoIface   = (*Store)(nil)
+	_ repository.DateHashRepoIface = (*Store)(nil)
+)
+
+type storedTask struct {
+	task   models.Task
+	typeID int
+}
+
+// Store keeps tasks, employees and the processing status in memory.
+type Store struct {
+	mu            sync.RWMutex
+	taskTypes     map[string]int
+	tasks         map[int]storedTask
+	employees     map[int]models.Employee
+	dismissed     map[int]bool
+	dateHashes    map[time.Time]string
+	lastProcessed time.Time
+	hasProcessed  bool
+	writes        int
+}
+
+// New creates an empty Store.
+func New() *Store {
+	return &Store{
+		taskTypes:  make(map[string]int),
+		tasks:      make(map[int]storedTask),
+		employees:  make(map[int]models.Employee),
+		dismissed:  make(map[int]bool),
+		dateHashes: make(map[time.Time]string),
+	}
+}
+
+// Writes returns the number of write operations performed on the store.
+func (s *Store) Writes() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.writes
+}
+
+// Task returns the stored task with the given ID.
+func (s *Store) Task(id int) (models.Task, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored, ok := s.tasks[id]
+	return cloneTask(stored.task), ok
+}
+
+// Employee returns the stored employee with the given ID.
+func (s *Store) Employee(id int) (models.Employee, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	employee, ok := s.employees[id]
+	return employee, ok
+}
+
+// Dismissed reports whether the employee with the given ID was marked as dismissed.
+func (s *Store) Dismissed(id int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.dismissed[id]
+}
+
+func (s *Store) GetOrCreateTaskTypeID(_ context.Context, typeName string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if typeID, ok := s.taskTypes[typeName]; ok {
+		return typeID, nil
+	}
+
+	typeID := len(s.taskTypes) + 1
+	s.taskTypes[typeName] = typeID
+	s.writes++
+
+	return typeID, nil
+}
+
+func (s *Store) UpsertTask(_ context.Context, task models.Task, typeID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Executors are kept separately, as in the task_executors table.
+	task = cloneTask(task)
+	task.Executors = s.tasks[task.ID].task.Executors
+	s.tasks[task.ID] = storedTask{task: task, typeID: typeID}
+	s.writes++
+
+	return nil
+}
+
+func (s *Store) GetTaskClosedStates(_ context.Context, taskIDs []int) (map[int]bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	states := make(map[int]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		if stored, ok := s.tasks[id]; ok {
+			states[id] = stored.task.IsClosed
+		}
+	}
+
+	return states, nil
+}
+
+func (s *Store) UpdateTaskExecutors(_ context.Context, taskID int, executors []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.tasks[taskID]
+	if !ok {
+		return fmt.Errorf("failed to update executors: task '%d' does not exist", taskID)
+	}
+
+	stored.task.Executors = slices.Clone(executors)
+	s.tasks[taskID] = stored
+	s.writes++
+
+	return nil
+}
+
+func (s *Store) UpdateTaskComments(_ context.Context, taskID int, comments []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.tasks[taskID]
+	if !ok {
+		return fmt.Errorf("failed to update comments: task '%d' does not exist", taskID)
+	}
+
+	stored.task.Comments = slices.Clone(comments)
+	s.tasks[taskID] = stored
+	s.writes++
+
+	return nil
+}
+
+func (s *Store) SaveTaskData(ctx context.Context, task models.Task) error {
+	typeID, err := s.GetOrCreateTaskTypeID(ctx, task.Type)
+	if err != nil {
+		return fmt.Errorf("task type preparation error: %w", err)
+	}
+
+	if err = s.UpsertTask(ctx, task, typeID); err != nil {
+		return fmt.Errorf("task insert/update error: %w", err)
+	}
+
+	if err = s.UpdateTaskExecutors(ctx, task.ID, task.Executors); err != nil {
+		return fmt.Errorf("error updating executors: %w", err)
+	}
+
+	if err = s.UpdateTaskComments(ctx, task.ID, task.Comments); err != nil {
+		return fmt.Errorf("error updating comments: %w", err)
+	}
+
+	return nil
+}
+
+// BackfillTaskContentHashes does nothing: content hashes are not stored in memory.
+func (s *Store) BackfillTaskContentHashes(_ context.Context, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("invalid batch size %d: must be positive", batchSize)
+	}
+
+	return 0, nil
+}
+
+// AnalyzeTables does nothing: there are no planner statistics in memory.
+func (s *Store) AnalyzeTables(_ context.Context, _ ...string) error {
+	return nil
+}
+
+func (s *Store) DeleteTasksOlderThan(_ context.Context, cutoff time.Time, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("invalid batch size %d: must be positive", batchSize)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for id, stored := range s.tasks {
+		if stored.task.CreatedAt.Before(cutoff) {
+			delete(s.tasks, id)
+			deleted++
+		}
+	}
+	if deleted > 0 {
+		s.writes++
+	}
+
+	return deleted, nil
+}
+
+func (s *Store) SaveEmployee(
+	_ context.Context,
+	identifier int,
+	fullname, shortname, position, email, phone string,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.employees[identifier]; ok {
+		// same as the ON CONFLICT (id) clause, which only reactivates a dismissed employee
+		if s.dismissed[identifier] {
+			delete(s.dismissed, identifier)
+			s.writes++
+		}
+		return nil
+	}
+
+	s.employees[identifier] = models.Employee{
+		ID: identifier, FullName: fullname, ShortName: shortname, Position: position, Email: email, Phone: phone,
+	}
+	s.writes++
+
+	return nil
+}
+
+func (s *Store) UpdateEmployee(
+	_ context.Context,
+	identifier int,
+	fullname, shortname, position, email, phone string,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.employees[identifier]; !ok {
+		return nil // same as an UPDATE matching no rows
+	}
+
+	s.employees[identifier] = models.Employee{
+		ID: identifier, FullName: fullname, ShortName: shortname, Position: position, Email: email, Phone: phone,
+	}
+	delete(s.dismissed, identifier)
+	s.writes++
+
+	return nil
+}
+
+func (s *Store) GetEmployeesByPosition(_ context.Context, position string) ([]models.Employee, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	employees := make([]models.Employee, 0)
+	for _, employee := range s.employees {
+		if employee.Position == position {
+			employees = append(employees, employee)
+		}
+	}
+	slices.SortFunc(employees, func(a, b models.Employee) int {
+		return strings.Compare(a.FullName, b.FullName)
+	})
+
+	return employees, nil
+}
+
+func (s *Store) UpsertEmployees(_ context.Context, employees []models.Employee) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, employee := range employees {
+		if stored, ok := s.employees[employee.ID]; ok && stored == employee && !s.dismissed[employee.ID] {
+			continue // same as the IS DISTINCT FROM guard
+		}
+		s.employees[employee.ID] = employee
+		delete(s.dismissed, employee.ID)
+		s.writes++
+	}
+
+	return nil
+}
+
+func (s *Store) GetEmployeeByID(_ context.Context, identifier int) (models.Employee, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	employee, ok := s.employees[identifier]
+	if !ok {
+		return models.Employee{}, fmt.Errorf("failed to get employee by id: %w", pgx.ErrNoRows)
+	}
+
+	return employee, nil
+}
+
+func (s *Store) MarkEmployeeDismissed(_ context.Context, identifier int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.employees[identifier]; !ok {
+		return fmt.Errorf("failed to mark employee %d as dismissed: %w", identifier, pgx.ErrNoRows)
+	}
+	if !s.dismissed[identifier] {
+		s.dismissed[identifier] = true
+		s.writes++
+	}
+
+	return nil
+}
+
+func (s *Store) GetActiveEmployeeIDs(_ context.Context) ([]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]int, 0, len(s.employees))
+	for id := range s.employees {
+		if !s.dismissed[id] {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	return ids, nil
+}
+
+func (s *Store) SaveProcessedDate(_ context.Context, date time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastProcessed = date
+	s.hasProcessed = true
+	s.writes++
+
+	return nil
+}
+
+func (s *Store) GetLastProcessedDate(_ context.Context) (time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.hasProcessed {
+		return time.Time{}, fmt.Errorf("failed to get last processed date: %w", pgx.ErrNoRows)
+	}
+
+	return s.lastProcessed, nil
+}
+
+func (s *Store) GetDateHash(_ context.Context, date time.Time) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.dateHashes[date.UTC()], nil
+}
+
+func (s *Store) SaveDateHash(_ context.Context, date time.Time, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dateHashes[date.UTC()] = hash
+	s.writes++
+
+	return nil
+}
+
+func cloneTask(task models.Task) models.Task {
+	task.Comments = slices.Clone(task.Comments)
+	task.Executors = slices.Clone(task.Executors)
+	return task
+}
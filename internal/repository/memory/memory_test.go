@@ -0,0 +1,102 @@
+package memory_test
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/models"
+	"github.com/UnknownOlympus/hephaestus/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Tasks(t *testing.T) {
+	store := memory.New()
+	task := models.Task{ID: 1, Type: "Repair", Description: "broken cable", Executors: []string{"Doe J."}}
+
+	require.NoError(t, store.SaveTaskData(t.Context(), task))
+	task.Description = "cable replaced"
+	task.Executors = []string{"Roe R."}
+	require.NoError(t, store.SaveTaskData(t.Context(), task))
+
+	stored, ok := store.Task(1)
+	require.True(t, ok)
+	assert.Equal(t, task, stored)
+
+	typeID, err := store.GetOrCreateTaskTypeID(t.Context(), "Repair")
+	require.NoError(t, err)
+	assert.Equal(t, 1, typeID)
+}
+
+func TestStore_Employees(t *testing.T) {
+	store := memory.New()
+
+	_, err := store.GetEmployeeByID(t.Context(), 1)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, store.SaveEmployee(t.Context(), 1, "John Doe", "Doe J.", "Engineer", "j@doe.com", "+1"))
+	require.NoError(t, store.SaveEmployee(t.Context(), 1, "Ignored", "Ignored", "", "", ""))
+	require.NoError(t, store.UpdateEmployee(t.Context(), 1, "John Doe", "Doe J.", "Lead", "j@doe.com", "+1"))
+
+	employee, err := store.GetEmployeeByID(t.Context(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Lead", employee.Position)
+	assert.Equal(t, 2, store.Writes())
+
+	require.ErrorIs(t, store.MarkEmployeeDismissed(t.Context(), 2), sql.ErrNoRows)
+	require.NoError(t, store.MarkEmployeeDismissed(t.Context(), 1))
+	assert.True(t, store.Dismissed(1))
+	_, err = store.GetEmployeeByID(t.Context(), 1)
+	require.NoError(t, err, "dismissed employees are still returned")
+
+	writes := store.Writes()
+	require.NoError(t, store.UpsertEmployees(t.Context(), []models.Employee{
+		employee,
+		{ID: 2, FullName: "Jane Roe", ShortName: "Roe J."},
+	}))
+	assert.Equal(t, writes+2, store.Writes(), "unchanged active employees are not rewritten")
+	assert.False(t, store.Dismissed(1), "upserted employees are reactivated")
+
+	require.NoError(t, store.MarkEmployeeDismissed(t.Context(), 1))
+	require.NoError(t, store.SaveEmployee(t.Context(), 1, "Ignored", "Ignored", "", "", ""))
+	assert.False(t, store.Dismissed(1), "saved employees are reactivated")
+
+	require.NoError(t, store.MarkEmployeeDismissed(t.Context(), 1))
+	require.NoError(t, store.UpdateEmployee(t.Context(), 1, "John Doe", "Doe J.", "Lead", "j@doe.com", "+1"))
+	assert.False(t, store.Dismissed(1), "updated employees are reactivated")
+}
+
+func TestStore_Status(t *testing.T) {
+	store := memory.New()
+
+	_, err := store.GetLastProcessedDate(t.Context())
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, store.SaveProcessedDate(t.Context(), date))
+
+	lastDate, err := store.GetLastProcessedDate(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, date, lastDate)
+}
+
+func TestStore_ConcurrentWrites(t *testing.T) {
+	store := memory.New()
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, store.SaveTaskData(t.Context(), models.Task{ID: i, Type: "Repair"}))
+		}()
+	}
+	wg.Wait()
+
+	for i := range 50 {
+		_, ok := store.Task(i)
+		assert.True(t, ok)
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/UnknownOlympus/hephaestus/internal/metrics"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrPoolExhausted is returned when no connection could be acquired from the pool before
+// the context deadline, as opposed to a query that was slow once it got a connection.
+var ErrPoolExhausted = errors.New("database connection pool exhausted")
+
+// poolGuard wraps a Database and marks connection acquisition timeouts with ErrPoolExhausted.
+type poolGuard struct {
+	db      Database
+	metrics *metrics.Metrics
+}
+
+func guardPool(db Database, metrics *metrics.Metrics) Database {
+	return poolGuard{db: db, metrics: metrics}
+}
+
+func (g poolGuard) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	tag, err := g.db.Exec(ctx, sql, arguments...)
+	return tag, g.check(err)
+}
+
+func (g poolGuard) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := g.db.Query(ctx, sql, args...)
+	return rows, g.check(err)
+}
+
+func (g poolGuard) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return guardedRow{Row: g.db.QueryRow(ctx, sql, args...), guard: g}
+}
+
+func (g poolGuard) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := g.db.Begin(ctx)
+	return tx, g.check(err)
+}
+
+func (g poolGuard) check(err error) error {
+	if !isAcquireTimeout(err) {
+		return err
+	}
+
+	g.metrics.PoolExhausted.Inc()
+	return fmt.Errorf("%w: %w", ErrPoolExhausted, err)
+}
+
+// guardedRow defers the check to Scan, where pgx reports errors of QueryRow.
+type guardedRow struct {
+	pgx.Row
+
+	guard poolGuard
+}
+
+func (r guardedRow) Scan(dest ...any) error {
+	return r.guard.check(r.Row.Scan(dest...))
+}
+
+// isAcquireTimeout reports whether err is a deadline hit while waiting for a pool connection.
+// Deadlines hit while connecting or on an acquired connection are wrapped by pgconn, whereas
+// the pool returns the bare context error when no connection becomes free in time.
+func isAcquireTimeout(err error) bool {
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var connectErr *pgconn.ConnectError
+	return !pgconn.Timeout(err) && !pgconn.SafeToRetry(err) && !errors.As(err, &connectErr)
+}
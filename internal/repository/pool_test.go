@@ -0,0 +1,75 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/metrics"
+	"github.com/UnknownOlympus/hephaestus/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolExhausted(t *testing.T) {
+	t.Parallel()
+
+	t.Run("acquisition timeout on exec", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+		mock.ExpectExec("INSERT INTO scraper_status").WithArgs(pgxmock.AnyArg()).
+			WillReturnError(context.DeadlineExceeded)
+
+		err = repository.NewStatusRepository(mock, testMetrics).SaveProcessedDate(t.Context(), time.Now())
+
+		require.ErrorIs(t, err, repository.ErrPoolExhausted)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.InDelta(t, 1, testutil.ToFloat64(testMetrics.PoolExhausted), 0)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("acquisition timeout on query row", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+		mock.ExpectQuery("SELECT last_processed_date").
+			WillReturnError(fmt.Errorf("acquire: %w", context.DeadlineExceeded))
+
+		_, err = repository.NewStatusRepository(mock, testMetrics).GetLastProcessedDate(t.Context())
+
+		require.ErrorIs(t, err, repository.ErrPoolExhausted)
+		assert.InDelta(t, 1, testutil.ToFloat64(testMetrics.PoolExhausted), 0)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("other errors are left untouched", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+		mock.ExpectQuery("SELECT last_processed_date").WillReturnError(assert.AnError)
+
+		_, err = repository.NewStatusRepository(mock, testMetrics).GetLastProcessedDate(t.Context())
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.NotErrorIs(t, err, repository.ErrPoolExhausted)
+		assert.InDelta(t, 0, testutil.ToFloat64(testMetrics.PoolExhausted), 0)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
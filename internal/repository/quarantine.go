@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ReplaceQuarantined makes items the set of quarantined items of the given type: items that are
+// no longer failing are released, new ones are added, and items that are still failing keep their
+// original quarantine time so that their age keeps growing.
+func (r *Repository) ReplaceQuarantined(ctx context.Context, itemType string, items map[int]string) error {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("replace_quarantined").Observe(duration)
+	}()
+
+	ids := make([]int, 0, len(items))
+	for id := range items {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	_, err := r.db.Exec(ctx,
+		"DELETE FROM quarantined_items WHERE item_type = $1 AND NOT (item_id = ANY($2))", itemType, ids)
+	if err != nil {
+		return fmt.Errorf("failed to release quarantined %s items: %w", itemType, err)
+	}
+
+	query := `
+		INSERT INTO quarantined_items (item_type, item_id, reason)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (item_type, item_id) DO UPDATE SET reason = EXCLUDED.reason;
+	`
+	for _, id := range ids {
+		if _, err = r.db.Exec(ctx, query, itemType, id, items[id]); err != nil {
+			return fmt.Errorf("failed to quarantine %s '%d': %w", itemType, id, err)
+		}
+	}
+
+	return nil
+}
+
+// GetOldestQuarantinedAt returns when the oldest quarantined item of the given type was
+// quarantined, or the zero time if there are none.
+func (r *Repository) GetOldestQuarantinedAt(ctx context.Context, itemType string) (time.Time, error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("get_oldest_quarantined_at").Observe(duration)
+	}()
+
+	var oldest *time.Time
+	err := r.db.QueryRow(ctx,
+		"SELECT MIN(quarantined_at) FROM quarantined_items WHERE item_type = $1", itemType).Scan(&oldest)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get oldest quarantined %s item: %w", itemType, err)
+	}
+
+	if oldest == nil {
+		return time.Time{}, nil
+	}
+
+	return *oldest, nil
+}
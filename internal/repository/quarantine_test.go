@@ -0,0 +1,115 @@
+package repository_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceQuarantined(t *testing.T) {
+	t.Parallel()
+
+	deleteQuery := regexp.QuoteMeta(
+		"DELETE FROM quarantined_items WHERE item_type = $1 AND NOT (item_id = ANY($2))")
+	insertQuery := regexp.QuoteMeta("INSERT INTO quarantined_items (item_type, item_id, reason)")
+
+	t.Run("releases fixed items and upserts failing ones", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(deleteQuery).WithArgs("employee", []int{2, 5}).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectExec(insertQuery).WithArgs("employee", 2, "timeout").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(insertQuery).WithArgs("employee", 5, "constraint violation").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		repo := repository.NewQuarantineRepository(mock, repoMetrics)
+		err = repo.ReplaceQuarantined(t.Context(), "employee", map[int]string{5: "constraint violation", 2: "timeout"})
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty set releases everything", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(deleteQuery).WithArgs("employee", []int{}).
+			WillReturnResult(pgxmock.NewResult("DELETE", 3))
+
+		repo := repository.NewQuarantineRepository(mock, repoMetrics)
+		err = repo.ReplaceQuarantined(t.Context(), "employee", nil)
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete error", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(deleteQuery).WithArgs("employee", []int{1}).WillReturnError(assert.AnError)
+
+		repo := repository.NewQuarantineRepository(mock, repoMetrics)
+		err = repo.ReplaceQuarantined(t.Context(), "employee", map[int]string{1: "timeout"})
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetOldestQuarantinedAt(t *testing.T) {
+	t.Parallel()
+
+	query := regexp.QuoteMeta("SELECT MIN(quarantined_at) FROM quarantined_items WHERE item_type = $1")
+
+	t.Run("returns the oldest time", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		oldest := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(query).WithArgs("employee").
+			WillReturnRows(pgxmock.NewRows([]string{"min"}).AddRow(&oldest))
+
+		got, err := repository.NewQuarantineRepository(mock, repoMetrics).GetOldestQuarantinedAt(t.Context(), "employee")
+
+		require.NoError(t, err)
+		assert.Equal(t, oldest, got)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("returns zero time when nothing is quarantined", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(query).WithArgs("employee").
+			WillReturnRows(pgxmock.NewRows([]string{"min"}).AddRow(nil))
+
+		got, err := repository.NewQuarantineRepository(mock, repoMetrics).GetOldestQuarantinedAt(t.Context(), "employee")
+
+		require.NoError(t, err)
+		assert.True(t, got.IsZero())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -19,7 +19,48 @@ type StatusRepoIface interface {
 }
 
 func NewStatusRepository(db Database, metrics *metrics.Metrics) StatusRepoIface {
-	return &Repository{db: db, metrics: metrics}
+	return &Repository{db: guardPool(db, metrics), metrics: metrics}
+}
+
+// SchemaRepoIface verifies that the database schema matches what the service expects.
+type SchemaRepoIface interface {
+	CheckSchema(ctx context.Context) error
+}
+
+func NewSchemaRepository(db Database, metrics *metrics.Metrics) SchemaRepoIface {
+	return &Repository{db: guardPool(db, metrics), metrics: metrics}
+}
+
+// DateHashRepoIface stores the Hermes hash of the tasks last received for each date.
+type DateHashRepoIface interface {
+	GetDateHash(ctx context.Context, date time.Time) (string, error)
+	SaveDateHash(ctx context.Context, date time.Time, hash string) error
+}
+
+func NewDateHashRepository(db Database, metrics *metrics.Metrics) DateHashRepoIface {
+	return &Repository{db: guardPool(db, metrics), metrics: metrics}
+}
+
+// QuarantineRepoIface stores items that failed to save without failing the whole run.
+type QuarantineRepoIface interface {
+	ReplaceQuarantined(ctx context.Context, itemType string, items map[int]string) error
+	GetOldestQuarantinedAt(ctx context.Context, itemType string) (time.Time, error)
+}
+
+func NewQuarantineRepository(db Database, metrics *metrics.Metrics) QuarantineRepoIface {
+	return &Repository{db: guardPool(db, metrics), metrics: metrics}
+}
+
+// FailedTaskRepoIface stores the tasks that repeatedly failed to save, so that they can be
+// inspected and retried without blocking the rest of the run.
+type FailedTaskRepoIface interface {
+	SaveFailedTask(ctx context.Context, task models.Task, attempts int, cause string) error
+	ListFailedTasks(ctx context.Context) ([]models.FailedTask, error)
+	DeleteFailedTask(ctx context.Context, taskID int) error
+}
+
+func NewFailedTaskRepository(db Database, metrics *metrics.Metrics) FailedTaskRepoIface {
+	return &Repository{db: guardPool(db, metrics), metrics: metrics}
 }
 
 // EmployeeRepoIface represents the interface for interacting with employee data in the repository.
@@ -27,20 +68,52 @@ type EmployeeRepoIface interface {
 	SaveEmployee(ctx context.Context, identifier int, fullname, shortname, position, email, phone string) error
 	UpdateEmployee(ctx context.Context, identifier int, fullname, shortname, position, email, phone string) error
 	GetEmployeeByID(ctx context.Context, identifier int) (models.Employee, error)
+	GetEmployeesByPosition(ctx context.Context, position string) ([]models.Employee, error)
+	MarkEmployeeDismissed(ctx context.Context, identifier int) error
+	GetActiveEmployeeIDs(ctx context.Context) ([]int, error)
+	UpsertEmployees(ctx context.Context, employees []models.Employee) error
 }
 
 func NewEmployeeRepository(db Database, metrics *metrics.Metrics) EmployeeRepoIface {
-	return &Repository{db: db, metrics: metrics}
+	return &Repository{db: guardPool(db, metrics), metrics: metrics}
 }
 
 // TaskRepoIface represents the interface for interacting with task data in the repository.
 type TaskRepoIface interface {
 	GetOrCreateTaskTypeID(ctx context.Context, typeName string) (int, error)
 	UpsertTask(ctx context.Context, task models.Task, typeID int) error
+	GetTaskClosedStates(ctx context.Context, taskIDs []int) (map[int]bool, error)
 	UpdateTaskExecutors(ctx context.Context, taskID int, executors []string) error
+	UpdateTaskComments(ctx context.Context, taskID int, comments []string) error
 	SaveTaskData(ctx context.Context, task models.Task) error
+	BackfillTaskContentHashes(ctx context.Context, batchSize int) (int, error)
+	AnalyzeTables(ctx context.Context, tables ...string) error
+	DeleteTasksOlderThan(ctx context.Context, cutoff time.Time, batchSize int) (int64, error)
 }
 
 func NewTaskRepository(db Database, metrics *metrics.Metrics) TaskRepoIface {
-	return &Repository{db: db, metrics: metrics}
+	return &Repository{db: guardPool(db, metrics), metrics: metrics}
+}
+
+// StatsRepoIface returns totals of the stored data without reading the rows themselves.
+type StatsRepoIface interface {
+	CountEmployees(ctx context.Context) (int, error)
+	CountTasks(ctx context.Context, onlyOpen bool) (int, error)
+}
+
+func NewStatsRepository(db Database, metrics *metrics.Metrics) StatsRepoIface {
+	return &Repository{db: guardPool(db, metrics), metrics: metrics}
+}
+
+// TaskQueryIface represents the interface for reading stored task data from the repository.
+type TaskQueryIface interface {
+	GetStuckTasks(ctx context.Context, olderThan time.Duration) ([]models.Task, error)
+	GetTaskByID(ctx context.Context, id int) (models.Task, error)
+	ListTasks(ctx context.Context, filter TaskFilter) ([]models.Task, error)
+	StreamTasksByDateRange(ctx context.Context, from, to time.Time, fn func(models.Task) error) error
+	StreamTasksByExecutor(ctx context.Context, shortname string, fn func(models.Task) error) error
+}
+
+func NewTaskQueryRepository(db Database, metrics *metrics.Metrics) TaskQueryIface {
+	return &Repository{db: guardPool(db, metrics), metrics: metrics}
 }
@@ -0,0 +1,47 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Every repository interface must be implemented by the concrete Repository itself, so that a
+// method renamed on one side only fails to compile instead of being silently satisfied elsewhere.
+var (
+	_ repository.StatusRepoIface     = (*repository.Repository)(nil)
+	_ repository.SchemaRepoIface     = (*repository.Repository)(nil)
+	_ repository.DateHashRepoIface   = (*repository.Repository)(nil)
+	_ repository.QuarantineRepoIface = (*repository.Repository)(nil)
+	_ repository.EmployeeRepoIface   = (*repository.Repository)(nil)
+	_ repository.TaskRepoIface       = (*repository.Repository)(nil)
+	_ repository.TaskQueryIface      = (*repository.Repository)(nil)
+	_ repository.StatsRepoIface      = (*repository.Repository)(nil)
+)
+
+// TestStatusRepoIface_SaveProcessedDate checks that the interface method and the concrete
+// Repository method are the same code path: both run the scraper_status upsert.
+func TestStatusRepoIface_SaveProcessedDate(t *testing.T) {
+	t.Parallel()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for range 2 {
+		mock.ExpectExec("INSERT INTO scraper_status").WithArgs(date).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	}
+
+	statusRepo := repository.NewStatusRepository(mock, repoMetrics)
+	concrete, ok := statusRepo.(*repository.Repository)
+	require.True(t, ok, "NewStatusRepository must return the concrete Repository")
+
+	require.NoError(t, statusRepo.SaveProcessedDate(t.Context(), date))
+	require.NoError(t, concrete.SaveProcessedDate(t.Context(), date))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
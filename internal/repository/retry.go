@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Backoff between write retries: it starts at retryBaseDelay and doubles up to retryMaxDelay.
+const (
+	retryBaseDelay = 100 * time.Millisecond
+	retryMaxDelay  = 2 * time.Second
+)
+
+// writeRetry wraps a Database and retries writes that failed on a transient error.
+type writeRetry struct {
+	db      Database
+	retries int
+}
+
+// WithWriteRetry returns db with every Exec retried up to retries times, with bounded
+// exponential backoff, when it fails on a transient error such as a dropped connection or
+// a server shutting down during failover. Constraint violations and other errors are
+// returned immediately. Zero or negative retries return db unchanged.
+func WithWriteRetry(db Database, retries int) Database {
+	if retries <= 0 {
+		return db
+	}
+
+	return writeRetry{db: db, retries: retries}
+}
+
+func (w writeRetry) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		tag, err := w.db.Exec(ctx, sql, arguments...)
+		if err == nil || attempt == w.retries || !isTransient(err) {
+			return tag, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return tag, err
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, retryMaxDelay)
+	}
+}
+
+func (w writeRetry) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return w.db.Query(ctx, sql, args...)
+}
+
+func (w writeRetry) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return w.db.QueryRow(ctx, sql, args...)
+}
+
+// Begin is not retried: statements in a transaction cannot be retried one by one.
+func (w writeRetry) Begin(ctx context.Context) (pgx.Tx, error) {
+	return w.db.Begin(ctx)
+}
+
+// isTransient reports whether err is worth retrying: the statement was never sent, or the
+// server reported a connection problem, a shutdown, a serialization failure or a deadlock.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "57P01", // admin_shutdown
+			"57P02", // crash_shutdown
+			"57P03", // cannot_connect_now
+			"40001", // serialization_failure
+			"40P01": // deadlock_detected
+			return true
+		}
+		// Class 08: connection exception.
+		return len(pgErr.Code) == 5 && pgErr.Code[:2] == "08"
+	}
+
+	return pgconn.SafeToRetry(err)
+}
@@ -0,0 +1,83 @@
+package repository_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/UnknownOlympus/hephaestus/internal/repository"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithWriteRetry(t *testing.T) {
+	t.Parallel()
+	adminShutdown := &pgconn.PgError{Code: "57P01", Message: "terminating connection due to administrator command"}
+
+	t.Run("success - retries a transient error", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(saveEmployeeQuery)).
+			WithArgs(1, "John Doe", "Doe J.", "qa", "j@doe.com", "+1").
+			WillReturnError(adminShutdown)
+		mock.ExpectExec(regexp.QuoteMeta(saveEmployeeQuery)).
+			WithArgs(1, "John Doe", "Doe J.", "qa", "j@doe.com", "+1").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		repo := repository.NewEmployeeRepository(repository.WithWriteRetry(mock, 3), repoMetrics)
+		err = repo.SaveEmployee(t.Context(), 1, "John Doe", "Doe J.", "qa", "j@doe.com", "+1")
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - constraint violation is not retried", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		uniqueViolation := &pgconn.PgError{Code: "23505", Message: "duplicate key value violates unique constraint"}
+		mock.ExpectExec(regexp.QuoteMeta(saveEmployeeQuery)).
+			WithArgs(1, "John Doe", "Doe J.", "qa", "j@doe.com", "+1").
+			WillReturnError(uniqueViolation)
+
+		repo := repository.NewEmployeeRepository(repository.WithWriteRetry(mock, 3), repoMetrics)
+		err = repo.SaveEmployee(t.Context(), 1, "John Doe", "Doe J.", "qa", "j@doe.com", "+1")
+
+		require.ErrorIs(t, err, uniqueViolation)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - gives up after the configured retries", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		for range 2 {
+			mock.ExpectExec(regexp.QuoteMeta(saveEmployeeQuery)).
+				WithArgs(1, "John Doe", "Doe J.", "qa", "j@doe.com", "+1").
+				WillReturnError(adminShutdown)
+		}
+
+		repo := repository.NewEmployeeRepository(repository.WithWriteRetry(mock, 1), repoMetrics)
+		err = repo.SaveEmployee(t.Context(), 1, "John Doe", "Doe J.", "qa", "j@doe.com", "+1")
+
+		require.ErrorIs(t, err, adminShutdown)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("zero retries leave the database unchanged", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		assert.Same(t, mock, repository.WithWriteRetry(mock, 0))
+	})
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrSchemaMismatch is returned by CheckSchema when the database lacks columns the service relies on.
+var ErrSchemaMismatch = errors.New("database schema mismatch")
+
+// requiredColumns returns the columns the service reads or writes, per table. Tables and columns
+// added by the files in migrations/ are only present once they were applied with `make migrate`.
+func requiredColumns() map[string][]string {
+	return map[string][]string{
+		"tasks": {
+			"task_id", "task_type_id", "creation_date", "closing_date", "description", "address",
+			"customer_name", "customer_login", "comments", "is_closed", "content_hash", "updated_at",
+			"latitude", "longitude", "geocoding_attempts", "geocoding_error",
+		},
+		"task_types":        {"type_id", "type_name"},
+		"task_executors":    {"task_id", "executor_id"},
+		"task_comments":     {"task_id", "position", "body"},
+		"task_date_hashes":  {"task_date", "hash", "updated_at"},
+		"failed_tasks":      {"task_id", "payload", "error", "attempts", "failed_at"},
+		"employees":         {"id", "fullname", "shortname", "position", "email", "phone", "is_active", "updated_at"},
+		"quarantined_items": {"item_type", "item_id", "reason", "quarantined_at"},
+		"scraper_status":    {"id", "last_processed_date", "updated_at"},
+	}
+}
+
+// CheckSchema verifies that every table and column the service relies on exists in the current schema.
+// It returns an error wrapping ErrSchemaMismatch that lists the missing columns.
+func (r *Repository) CheckSchema(ctx context.Context) error {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("check_schema").Observe(duration)
+	}()
+
+	required := requiredColumns()
+	tables := make([]string, 0, len(required))
+	for table := range required {
+		tables = append(tables, table)
+	}
+	slices.Sort(tables)
+
+	query := `
+		SELECT table_name, column_name
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ANY($1);
+	`
+
+	rows, err := r.db.Query(ctx, query, tables)
+	if err != nil {
+		return fmt.Errorf("failed to query schema columns: %w", err)
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var table, column string
+		if err = rows.Scan(&table, &column); err != nil {
+			return fmt.Errorf("failed to scan schema column: %w", err)
+		}
+		existing[table+"."+column] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate schema columns: %w", err)
+	}
+
+	var missing []string
+	for _, table := range tables {
+		for _, column := range required[table] {
+			if !existing[table+"."+column] {
+				missing = append(missing, table+"."+column)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing columns %s", ErrSchemaMismatch, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package repository_test
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/UnknownOlympus/hephaestus/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func schemaRows(exclude ...string) *pgxmock.Rows {
+	columns := map[string][]string{
+		"employees":         {"id", "fullname", "shortname", "position", "email", "phone", "is_active", "updated_at"},
+		"failed_tasks":      {"task_id", "payload", "error", "attempts", "failed_at"},
+		"quarantined_items": {"item_type", "item_id", "reason", "quarantined_at"},
+		"scraper_status":    {"id", "last_processed_date", "updated_at"},
+		"task_comments":     {"task_id", "position", "body"},
+		"task_date_hashes":  {"task_date", "hash", "updated_at"},
+		"task_executors":    {"task_id", "executor_id"},
+		"task_types":        {"type_id", "type_name"},
+		"tasks": {
+			"task_id", "task_type_id", "creation_date", "closing_date", "description", "address",
+			"customer_name", "customer_login", "comments", "is_closed", "content_hash", "updated_at",
+			"latitude", "longitude", "geocoding_attempts", "geocoding_error",
+		},
+	}
+
+	rows := pgxmock.NewRows([]string{"table_name", "column_name"})
+	for table, names := range columns {
+		for _, column := range names {
+			if !slices.Contains(exclude, table+"."+column) {
+				rows.AddRow(table, column)
+			}
+		}
+	}
+
+	return rows
+}
+
+func TestCheckSchema(t *testing.T) {
+	t.Parallel()
+
+	query := regexp.QuoteMeta("FROM information_schema.columns")
+	tables := []string{
+		"employees", "failed_tasks", "quarantined_items", "scraper_status", "task_comments", "task_date_hashes",
+		"task_executors", "task_types", "tasks",
+	}
+
+	t.Run("schema matches", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(query).WithArgs(tables).WillReturnRows(schemaRows())
+
+		err = repository.NewSchemaRepository(mock, repoMetrics).CheckSchema(t.Context())
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("missing columns", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(query).WithArgs(tables).WillReturnRows(schemaRows("tasks.content_hash", "employees.phone"))
+
+		err = repository.NewSchemaRepository(mock, repoMetrics).CheckSchema(t.Context())
+
+		require.ErrorIs(t, err, repository.ErrSchemaMismatch)
+		assert.Contains(t, err.Error(), "missing columns employees.phone, tasks.content_hash")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unmigrated database", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(query).WithArgs(tables).WillReturnRows(schemaRows(
+			"employees.is_active", "failed_tasks.task_id", "failed_tasks.payload", "failed_tasks.error",
+			"failed_tasks.attempts", "failed_tasks.failed_at", "quarantined_items.item_type",
+			"quarantined_items.item_id", "quarantined_items.reason", "quarantined_items.quarantined_at",
+			"task_date_hashes.task_date", "task_date_hashes.hash", "task_date_hashes.updated_at",
+		))
+
+		err = repository.NewSchemaRepository(mock, repoMetrics).CheckSchema(t.Context())
+
+		require.ErrorIs(t, err, repository.ErrSchemaMismatch)
+		assert.Contains(t, err.Error(), "employees.is_active, failed_tasks.task_id")
+		assert.Contains(t, err.Error(), "quarantined_items.item_type")
+		assert.Contains(t, err.Error(), "task_date_hashes.task_date")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(query).WithArgs(tables).WillReturnError(assert.AnError)
+
+		err = repository.NewSchemaRepository(mock, repoMetrics).CheckSchema(t.Context())
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.NotErrorIs(t, err, repository.ErrSchemaMismatch)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
 // SaveProcessedDate saves last processed date.
@@ -44,3 +47,45 @@ func (r *Repository) GetLastProcessedDate(ctx context.Context) (time.Time, error
 
 	return lastDate, nil
 }
+
+// GetDateHash returns the stored Hermes hash for the given date, or an empty string if none is stored.
+func (r *Repository) GetDateHash(ctx context.Context, date time.Time) (string, error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("get_date_hash").Observe(duration)
+	}()
+	query := "SELECT hash FROM task_date_hashes WHERE task_date = $1"
+
+	var hash string
+
+	err := r.db.QueryRow(ctx, query, date).Scan(&hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get hash for date '%s': %w", date.Format("2006-01-02"), err)
+	}
+
+	return hash, nil
+}
+
+// SaveDateHash stores the Hermes hash for the given date, replacing the previous one.
+func (r *Repository) SaveDateHash(ctx context.Context, date time.Time, hash string) error {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("save_date_hash").Observe(duration)
+	}()
+	query := `
+		INSERT INTO task_date_hashes (task_date, hash)
+		VALUES ($1, $2)
+		ON CONFLICT (task_date) DO UPDATE SET hash = EXCLUDED.hash, updated_at = CURRENT_TIMESTAMP;`
+
+	_, err := r.db.Exec(ctx, query, date, hash)
+	if err != nil {
+		return fmt.Errorf("failed to save hash for date '%s': %w", date.Format("2006-01-02"), err)
+	}
+
+	return nil
+}
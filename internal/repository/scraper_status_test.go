@@ -7,6 +7,7 @@ import (
 
 	"github.com/UnknownOlympus/hephaestus/internal/metrics"
 	"github.com/UnknownOlympus/hephaestus/internal/repository"
+	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v4"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
@@ -110,3 +111,100 @@ func TestGetLastProcessedDate_QueryError(t *testing.T) {
 	assert.Contains(t, err.Error(), assert.AnError.Error())
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestGetDateHash(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	query := "SELECT hash FROM task_date_hashes WHERE task_date = $1"
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(date).
+			WillReturnRows(pgxmock.NewRows([]string{"hash"}).AddRow("hash_1"))
+
+		hash, err := repository.NewDateHashRepository(mock, repoMetrics).GetDateHash(t.Context(), date)
+
+		require.NoError(t, err)
+		assert.Equal(t, "hash_1", hash)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no stored hash", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(date).WillReturnError(pgx.ErrNoRows)
+
+		hash, err := repository.NewDateHashRepository(mock, repoMetrics).GetDateHash(t.Context(), date)
+
+		require.NoError(t, err)
+		assert.Empty(t, hash)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(date).WillReturnError(assert.AnError)
+
+		_, err = repository.NewDateHashRepository(mock, repoMetrics).GetDateHash(t.Context(), date)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to get hash for date '2024-03-01'")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSaveDateHash(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	query := `
+		INSERT INTO task_date_hashes (task_date, hash)
+		VALUES ($1, $2)
+		ON CONFLICT (task_date) DO UPDATE SET hash = EXCLUDED.hash, updated_at = CURRENT_TIMESTAMP;`
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(date, "hash_1").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		err = repository.NewDateHashRepository(mock, repoMetrics).SaveDateHash(t.Context(), date, "hash_1")
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("exec error", func(t *testing.T) {
+		t.Parallel()
+
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(date, "hash_1").WillReturnError(assert.AnError)
+
+		err = repository.NewDateHashRepository(mock, repoMetrics).SaveDateHash(t.Context(), date, "hash_1")
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CountEmployees returns the number of stored employees.
+func (r *Repository) CountEmployees(ctx context.Context) (int, error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("count_employees").Observe(duration)
+	}()
+
+	var count int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM employees;").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count employees: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountTasks returns the number of stored tasks, or only of the open ones if onlyOpen is set.
+func (r *Repository) CountTasks(ctx context.Context, onlyOpen bool) (int, error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("count_tasks").Observe(duration)
+	}()
+
+	query := "SELECT COUNT(*) FROM tasks"
+	if onlyOpen {
+		query += " WHERE is_closed = false"
+	}
+
+	var count int
+	if err := r.db.QueryRow(ctx, query+";").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/UnknownOlympus/hephaestus/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountEmployees(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM employees;")).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(42))
+
+		count, err := repository.NewStatsRepository(mock, repoMetrics).CountEmployees(t.Context())
+
+		require.NoError(t, err)
+		assert.Equal(t, 42, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - query error", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM employees;")).
+			WillReturnError(assert.AnError)
+
+		_, err = repository.NewStatsRepository(mock, repoMetrics).CountEmployees(t.Context())
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCountTasks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		onlyOpen bool
+		query    string
+	}{
+		{name: "all tasks", onlyOpen: false, query: "SELECT COUNT(*) FROM tasks;"},
+		{name: "only open tasks", onlyOpen: true, query: "SELECT COUNT(*) FROM tasks WHERE is_closed = false;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			mock.ExpectQuery(regexp.QuoteMeta(tt.query)).
+				WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(7))
+
+			count, err := repository.NewStatsRepository(mock, repoMetrics).CountTasks(t.Context(), tt.onlyOpen)
+
+			require.NoError(t, err)
+			assert.Equal(t, 7, count)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("failure - query error", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM tasks")).
+			WillReturnError(assert.AnError)
+
+		_, err = repository.NewStatsRepository(mock, repoMetrics).CountTasks(t.Context(), true)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
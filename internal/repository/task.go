@@ -2,8 +2,12 @@ package repository
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/UnknownOlympus/hephaestus/internal/models"
@@ -22,26 +26,23 @@ func (r *Repository) GetOrCreateTaskTypeID(ctx context.Context, typeName string)
 		return typeID, nil // type is found, return id
 	}
 
-	insertQuery := `
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("request error to `task_types`: %w", err)
+	}
+
+	// Type not found, insert it. DO UPDATE (instead of DO NOTHING) makes RETURNING yield the ID
+	// even when another transaction inserted the same type between our SELECT and INSERT.
+	upsertQuery := `
 		INSERT INTO task_types (type_name)
 		VALUES ($1)
-		ON CONFLICT (type_name) DO NOTHING;
+		ON CONFLICT (type_name) DO UPDATE SET type_name = EXCLUDED.type_name
+		RETURNING type_id;
 	`
-	if errors.Is(err, pgx.ErrNoRows) {
-		// type not found, insert it
-		_, err = r.db.Exec(ctx, insertQuery, typeName)
-		if err != nil {
-			return 0, fmt.Errorf("error inserting new task type '%s': %w", typeName, err)
-		}
-		// now, we get the ID again (this covers the case if another transaction inserted it between our queries)
-		err = r.db.QueryRow(ctx, "SELECT type_id FROM task_types WHERE type_name = $1", typeName).Scan(&typeID)
-		if err != nil {
-			return 0, fmt.Errorf("error inserting ID for newly inserted task type '%s': %w", typeName, err)
-		}
-		return typeID, nil
+	if err = r.db.QueryRow(ctx, upsertQuery, typeName).Scan(&typeID); err != nil {
+		return 0, fmt.Errorf("error inserting new task type '%s': %w", typeName, err)
 	}
 
-	return 0, fmt.Errorf("request error to `task_types`: %w", err)
+	return typeID, nil
 }
 
 func (r *Repository) SaveTaskData(ctx context.Context, task models.Task) error {
@@ -68,6 +69,12 @@ func (r *Repository) SaveTaskData(ctx context.Context, task models.Task) error {
 		return fmt.Errorf("error updating executors: %w", err)
 	}
 
+	// 4. Update comments for the task
+	err = r.UpdateTaskComments(ctx, task.ID, task.Comments)
+	if err != nil {
+		return fmt.Errorf("error updating comments: %w", err)
+	}
+
 	return nil
 }
 
@@ -81,9 +88,9 @@ func (r *Repository) UpsertTask(ctx context.Context, task models.Task, typeID in
 	query := `
 		INSERT INTO tasks (
 			task_id, task_type_id, creation_date, closing_date, description,
-			address, customer_name, customer_login, comments, is_closed
+			address, customer_name, customer_login, comments, is_closed, content_hash
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		ON CONFLICT (task_id) DO UPDATE SET
 			task_type_id = EXCLUDED.task_type_id,
 			closing_date = EXCLUDED.closing_date,
@@ -92,6 +99,7 @@ func (r *Repository) UpsertTask(ctx context.Context, task models.Task, typeID in
 			customer_login = EXCLUDED.customer_login,
 			comments = EXCLUDED.comments,
 			is_closed = EXCLUDED.is_closed,
+			content_hash = EXCLUDED.content_hash,
 			updated_at = CURRENT_TIMESTAMP,
 			address = EXCLUDED.address,
 			latitude = CASE
@@ -114,6 +122,7 @@ func (r *Repository) UpsertTask(ctx context.Context, task models.Task, typeID in
 	_, err := r.db.Exec(ctx, query,
 		task.ID, typeID, task.CreatedAt, task.ClosedAt, task.Description,
 		task.Address, task.CustomerName, task.CustomerLogin, task.Comments, task.IsClosed,
+		TaskContentHash(task, typeID),
 	)
 	if err != nil {
 		return fmt.Errorf("upsert task error for task '%d': %w", task.ID, err)
@@ -122,6 +131,40 @@ func (r *Repository) UpsertTask(ctx context.Context, task models.Task, typeID in
 	return nil
 }
 
+// GetTaskClosedStates returns whether each of the given tasks is stored as closed.
+// Tasks that are not stored yet are absent from the result.
+func (r *Repository) GetTaskClosedStates(ctx context.Context, taskIDs []int) (map[int]bool, error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("get_task_closed_states").Observe(duration)
+	}()
+
+	rows, err := r.db.Query(ctx, "SELECT task_id, is_closed FROM tasks WHERE task_id = ANY($1)", taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get closed states of tasks: %w", err)
+	}
+	defer rows.Close()
+
+	states := make(map[int]bool, len(taskIDs))
+	for rows.Next() {
+		var (
+			taskID   int
+			isClosed bool
+		)
+		if err = rows.Scan(&taskID, &isClosed); err != nil {
+			return nil, fmt.Errorf("failed to scan task closed state: %w", err)
+		}
+		states[taskID] = isClosed
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate task closed states: %w", err)
+	}
+
+	return states, nil
+}
+
 func (r *Repository) UpdateTaskExecutors(ctx context.Context, taskID int, executors []string) error {
 	startTime := time.Now()
 	defer func() {
@@ -149,3 +192,137 @@ func (r *Repository) UpdateTaskExecutors(ctx context.Context, taskID int, execut
 
 	return nil
 }
+
+// UpdateTaskComments replaces the comments of a task in task_comments, keeping their order.
+// The replacement runs in a transaction, so a failure leaves the previous comments in place.
+func (r *Repository) UpdateTaskComments(ctx context.Context, taskID int, comments []string) error {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("update_task_comments").Observe(duration)
+	}()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for comments of the task '%d': %w", taskID, err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx) // no-op once committed
+	}()
+
+	// 1. Delete all comments for this task
+	_, err = tx.Exec(ctx, "DELETE FROM task_comments WHERE task_id = $1", taskID)
+	if err != nil {
+		return fmt.Errorf("failed to delete existing comments for the task '%d': %w", taskID, err)
+	}
+
+	// 2. Insert new comments
+	for i, comment := range comments {
+		_, err = tx.Exec(ctx, "INSERT INTO task_comments (task_id, position, body) VALUES ($1, $2, $3);",
+			taskID, i+1, comment)
+		if err != nil {
+			return fmt.Errorf("failed to save comment %d of the task '%d': %w", i+1, taskID, err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit comments of the task '%d': %w", taskID, err)
+	}
+
+	return nil
+}
+
+// BackfillTaskContentHashes computes the content hash for every task that does not have one yet.
+// Rows are processed in batches of batchSize until none are left. It returns the number of updated tasks.
+func (r *Repository) BackfillTaskContentHashes(ctx context.Context, batchSize int) (int, error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("backfill_task_content_hashes").Observe(duration)
+	}()
+
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("invalid batch size %d: must be positive", batchSize)
+	}
+
+	var total int
+	for {
+		tasks, err := r.getTasksWithoutContentHash(ctx, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		for _, item := range tasks {
+			_, err = r.db.Exec(ctx, "UPDATE tasks SET content_hash = $2 WHERE task_id = $1",
+				item.task.ID, TaskContentHash(item.task, item.typeID))
+			if err != nil {
+				return total, fmt.Errorf("failed to store content hash for task '%d': %w", item.task.ID, err)
+			}
+			total++
+		}
+
+		if len(tasks) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+type typedTask struct {
+	task   models.Task
+	typeID int
+}
+
+func (r *Repository) getTasksWithoutContentHash(ctx context.Context, limit int) ([]typedTask, error) {
+	query := `
+		SELECT task_id, task_type_id, creation_date, closing_date, description,
+			address, customer_name, customer_login, comments, is_closed
+		FROM tasks
+		WHERE content_hash IS NULL
+		ORDER BY task_id
+		LIMIT $1;
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks without content hash: %w", err)
+	}
+	defer rows.Close()
+
+	var result []typedTask
+	for rows.Next() {
+		var item typedTask
+		if err = rows.Scan(
+			&item.task.ID, &item.typeID, &item.task.CreatedAt, &item.task.ClosedAt, &item.task.Description,
+			&item.task.Address, &item.task.CustomerName, &item.task.CustomerLogin, &item.task.Comments,
+			&item.task.IsClosed,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan task row: %w", err)
+		}
+		result = append(result, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate task rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// TaskContentHash returns a stable hash of the task fields stored in the `tasks` table.
+// It is used to detect whether the stored row differs from freshly received data.
+func TaskContentHash(task models.Task, typeID int) string {
+	parts := []string{
+		strconv.Itoa(typeID),
+		task.CreatedAt.UTC().Format(time.RFC3339),
+		task.ClosedAt.UTC().Format(time.RFC3339),
+		task.Description,
+		task.Address,
+		task.CustomerName,
+		task.CustomerLogin,
+		strings.Join(task.Comments, "\x1e"),
+		strconv.FormatBool(task.IsClosed),
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
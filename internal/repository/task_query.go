@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// taskSelectQuery selects tasks with their type name and executor short names,
+// in the column order expected by scanTask.
+const taskSelectQuery = `
+	SELECT t.task_id, tt.type_name, t.creation_date, t.closing_date, t.description,
+		t.address, t.customer_name, t.customer_login, t.comments, t.is_closed,
+		COALESCE((
+			SELECT array_agg(e.shortname ORDER BY e.shortname)
+			FROM task_executors te
+			JOIN employees e ON e.id = te.executor_id
+			WHERE te.task_id = t.task_id
+		), '{}') AS executors
+	FROM tasks t
+	JOIN task_types tt ON tt.type_id = t.task_type_id
+`
+
+// GetStuckTasks returns open tasks created more than olderThan ago, oldest first.
+func (r *Repository) GetStuckTasks(ctx context.Context, olderThan time.Duration) ([]models.Task, error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("get_stuck_tasks").Observe(duration)
+	}()
+	query := taskSelectQuery + `
+	WHERE t.is_closed = false AND t.creation_date < now() - $1::interval
+	ORDER BY t.creation_date ASC;`
+
+	rows, err := r.db.Query(ctx, query, olderThan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stuck tasks: %w", err)
+	}
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stuck tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// GetTaskByID returns the stored task with the given id. If there is no such task,
+// the returned error wraps sql.ErrNoRows.
+func (r *Repository) GetTaskByID(ctx context.Context, id int) (models.Task, error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("get_task_by_id").Observe(duration)
+	}()
+	query := taskSelectQuery + `
+	WHERE t.task_id = $1;`
+
+	task, err := scanTask(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		return models.Task{}, fmt.Errorf("failed to get task %d: %w", id, err)
+	}
+
+	return task, nil
+}
+
+// TaskFilter narrows the tasks returned by ListTasks. Zero values mean no filtering.
+type TaskFilter struct {
+	// From and To limit the creation date to [From, To).
+	From time.Time
+	To   time.Time
+	// IsClosed, if not nil, selects only closed or only open tasks.
+	IsClosed      *bool
+	CustomerLogin string
+	Limit         int
+	Offset        int
+}
+
+// ListTasks returns the stored tasks matching filter, newest first.
+func (r *Repository) ListTasks(ctx context.Context, filter TaskFilter) ([]models.Task, error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("list_tasks").Observe(duration)
+	}()
+
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, strings.Replace(condition, "?", "$"+strconv.Itoa(len(args)), 1))
+	}
+
+	if !filter.From.IsZero() {
+		addCondition("t.creation_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCondition("t.creation_date < ?", filter.To)
+	}
+	if filter.IsClosed != nil {
+		addCondition("t.is_closed = ?", *filter.IsClosed)
+	}
+	if filter.CustomerLogin != "" {
+		addCondition("t.customer_login = ?", filter.CustomerLogin)
+	}
+
+	query := taskSelectQuery
+	if len(conditions) > 0 {
+		query += "\tWHERE " + strings.Join(conditions, " AND ") + "\n"
+	}
+	query += "\tORDER BY t.creation_date DESC, t.task_id DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += " OFFSET $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.db.Query(ctx, query+";", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// StreamTasksByDateRange calls fn for each task created in [from, to), oldest first,
+// as rows are read. Iteration stops at the first error returned by fn. The stream is not
+// bounded by the query timeout, only by the deadline of ctx.
+func (r *Repository) StreamTasksByDateRange(
+	ctx context.Context,
+	from, to time.Time,
+	fn func(models.Task) error,
+) error {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("stream_tasks_by_date_range").Observe(duration)
+	}()
+	query := taskSelectQuery + `
+	WHERE t.creation_date >= $1 AND t.creation_date < $2
+	ORDER BY t.creation_date ASC, t.task_id ASC;`
+
+	rows, err := r.db.Query(withoutQueryTimeout(ctx), query, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to query tasks by date range: %w", err)
+	}
+
+	return streamTasks(rows, fn)
+}
+
+// StreamTasksByExecutor calls fn for each task assigned to the employee with the given
+// short name, oldest first, as rows are read. Iteration stops at the first error returned by fn.
+// The stream is not bounded by the query timeout, only by the deadline of ctx.
+func (r *Repository) StreamTasksByExecutor(ctx context.Context, shortname string, fn func(models.Task) error) error {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime).Seconds()
+		r.metrics.DBQueryDuration.WithLabelValues("stream_tasks_by_executor").Observe(duration)
+	}()
+	query := taskSelectQuery + `
+	WHERE EXISTS (
+		SELECT 1 FROM task_executors te
+		JOIN employees e ON e.id = te.executor_id
+		WHERE te.task_id = t.task_id AND e.shortname = $1
+	)
+	ORDER BY t.creation_date ASC, t.task_id ASC;`
+
+	rows, err := r.db.Query(withoutQueryTimeout(ctx), query, shortname)
+	if err != nil {
+		return fmt.Errorf("failed to query tasks by executor: %w", err)
+	}
+
+	return streamTasks(rows, fn)
+}
+
+// streamTasks passes each row produced by taskSelectQuery to fn and closes the rows.
+// Errors returned by fn are passed through unwrapped.
+func streamTasks(rows pgx.Rows, fn func(models.Task) error) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return err
+		}
+		if err = fn(task); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate task rows: %w", err)
+	}
+
+	return nil
+}
+
+// scanTasks reads all rows produced by taskSelectQuery and closes them.
+func scanTasks(rows pgx.Rows) ([]models.Task, error) {
+	defer rows.Close()
+
+	tasks := make([]models.Task, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate task rows: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// scanTask reads a single row produced by taskSelectQuery.
+func scanTask(row pgx.Row) (models.Task, error) {
+	var task models.Task
+
+	err := row.Scan(
+		&task.ID, &task.Type, &task.CreatedAt, &task.ClosedAt, &task.Description,
+		&task.Address, &task.CustomerName, &task.CustomerLogin, &task.Comments, &task.IsClosed,
+		&task.Executors,
+	)
+	if err != nil {
+		return models.Task{}, fmt.Errorf("failed to scan task row: %w", err)
+	}
+
+	return task, nil
+}
@@ -0,0 +1,360 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/models"
+	"github.com/UnknownOlympus/hephaestus/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var taskColumns = []string{ //nolint:gochecknoglobals // for test case
+	"task_id", "type_name", "creation_date", "closing_date", "description",
+	"address", "customer_name", "customer_login", "comments", "is_closed", "executors",
+}
+
+// TestGetStuckTasks checks the query for open tasks older than a threshold.
+func TestGetStuckTasks(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	olderThan := 72 * time.Hour
+
+	t.Run("success - oldest first", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskQueryRepository(mock, repoMetrics)
+		oldest := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		older := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+
+		mock.ExpectQuery(`WHERE t.is_closed = false AND t.creation_date < now\(\) - \$1::interval\s+ORDER BY t.creation_date ASC`).
+			WithArgs(olderThan).
+			WillReturnRows(pgxmock.NewRows(taskColumns).
+				AddRow(1, "Repair", oldest, time.Time{}, "first", "addr 1", "John", "john1", []string{}, false,
+					[]string{"Doe J."}).
+				AddRow(2, "Install", older, time.Time{}, "second", "addr 2", "Jane", "jane2", []string{"call"}, false,
+					[]string{}))
+
+		tasks, err := repo.GetStuckTasks(ctx, olderThan)
+
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+		assert.Equal(t, models.Task{
+			ID: 1, Type: "Repair", CreatedAt: oldest, Description: "first", Address: "addr 1",
+			CustomerName: "John", CustomerLogin: "john1", Comments: []string{}, Executors: []string{"Doe J."},
+		}, tasks[0])
+		assert.Equal(t, 2, tasks[1].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success - empty result", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskQueryRepository(mock, repoMetrics)
+
+		mock.ExpectQuery("WHERE t.is_closed = false").
+			WithArgs(olderThan).
+			WillReturnRows(pgxmock.NewRows(taskColumns))
+
+		tasks, err := repo.GetStuckTasks(ctx, olderThan)
+
+		require.NoError(t, err)
+		assert.Empty(t, tasks)
+		assert.NotNil(t, tasks)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - query error", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskQueryRepository(mock, repoMetrics)
+
+		mock.ExpectQuery("WHERE t.is_closed = false").
+			WithArgs(olderThan).
+			WillReturnError(assert.AnError)
+
+		_, err = repo.GetStuckTasks(ctx, olderThan)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestGetTaskByID checks reading a single stored task.
+func TestGetTaskByID(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	t.Run("success - found", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskQueryRepository(mock, repoMetrics)
+		created := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		closed := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+
+		mock.ExpectQuery(`WHERE t.task_id = \$1`).
+			WithArgs(42).
+			WillReturnRows(pgxmock.NewRows(taskColumns).
+				AddRow(42, "Repair", created, closed, "desc", "addr", "John", "john1", []string{"call", "done"}, true,
+					[]string{"Doe J.", "Roe R."}))
+
+		task, err := repo.GetTaskByID(ctx, 42)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.Task{
+			ID: 42, Type: "Repair", CreatedAt: created, ClosedAt: closed, Description: "desc", Address: "addr",
+			CustomerName: "John", CustomerLogin: "john1", Comments: []string{"call", "done"}, IsClosed: true,
+			Executors: []string{"Doe J.", "Roe R."},
+		}, task)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - not found", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskQueryRepository(mock, repoMetrics)
+
+		mock.ExpectQuery(`WHERE t.task_id = \$1`).
+			WithArgs(42).
+			WillReturnRows(pgxmock.NewRows(taskColumns))
+
+		_, err = repo.GetTaskByID(ctx, 42)
+
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - db error", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskQueryRepository(mock, repoMetrics)
+
+		mock.ExpectQuery(`WHERE t.task_id = \$1`).
+			WithArgs(42).
+			WillReturnError(assert.AnError)
+
+		_, err = repo.GetTaskByID(ctx, 42)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.NotErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestListTasks checks the filters of the paginated task listing.
+func TestListTasks(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success - date range with pagination", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskQueryRepository(mock, repoMetrics)
+		closed := true
+
+		mock.ExpectQuery(`WHERE t.creation_date >= \$1 AND t.creation_date < \$2 AND t.is_closed = \$3\s+`+
+			`ORDER BY t.creation_date DESC, t.task_id DESC LIMIT \$4 OFFSET \$5;`).
+			WithArgs(from, to, true, 10, 20).
+			WillReturnRows(pgxmock.NewRows(taskColumns).
+				AddRow(2, "Install", from.Add(2*time.Hour), from.Add(3*time.Hour), "second", "addr 2", "Jane", "jane2",
+					[]string{}, true, []string{}).
+				AddRow(1, "Repair", from, from.Add(time.Hour), "first", "addr 1", "John", "john1", []string{}, true,
+					[]string{"Doe J."}))
+
+		tasks, err := repo.ListTasks(ctx, repository.TaskFilter{
+			From: from, To: to, IsClosed: &closed, Limit: 10, Offset: 20,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+		assert.Equal(t, 2, tasks[0].ID)
+		assert.Equal(t, 1, tasks[1].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success - customer login only", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskQueryRepository(mock, repoMetrics)
+
+		mock.ExpectQuery(`WHERE t.customer_login = \$1\s+ORDER BY t.creation_date DESC, t.task_id DESC;`).
+			WithArgs("john1").
+			WillReturnRows(pgxmock.NewRows(taskColumns))
+
+		tasks, err := repo.ListTasks(ctx, repository.TaskFilter{CustomerLogin: "john1"})
+
+		require.NoError(t, err)
+		assert.Empty(t, tasks)
+		assert.NotNil(t, tasks)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - query error", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskQueryRepository(mock, repoMetrics)
+
+		mock.ExpectQuery(`ORDER BY t.creation_date DESC`).
+			WithArgs().
+			WillReturnError(assert.AnError)
+
+		_, err = repo.ListTasks(ctx, repository.TaskFilter{})
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestStreamTasksByDateRange checks that tasks are passed to the callback row by row.
+func TestStreamTasksByDateRange(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := func() *pgxmock.Rows {
+		return pgxmock.NewRows(taskColumns).
+			AddRow(1, "Repair", from, time.Time{}, "first", "addr 1", "John", "john1", []string{}, false,
+				[]string{"Doe J."}).
+			AddRow(2, "Install", from.Add(time.Hour), time.Time{}, "second", "addr 2", "Jane", "jane2",
+				[]string{}, true, []string{}).
+			AddRow(3, "Repair", from.Add(2*time.Hour), time.Time{}, "third", "addr 3", "Jim", "jim3",
+				[]string{}, false, []string{})
+	}
+
+	t.Run("success - callback per row", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskQueryRepository(mock, repoMetrics)
+
+		mock.ExpectQuery(`WHERE t.creation_date >= \$1 AND t.creation_date < \$2`).
+			WithArgs(from, to).
+			WillReturnRows(rows())
+
+		var ids []int
+		err = repo.StreamTasksByDateRange(ctx, from, to, func(task models.Task) error {
+			ids = append(ids, task.ID)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - callback error stops iteration", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskQueryRepository(mock, repoMetrics)
+
+		mock.ExpectQuery(`WHERE t.creation_date >= \$1`).
+			WithArgs(from, to).
+			WillReturnRows(rows()).
+			RowsWillBeClosed()
+
+		var ids []int
+		err = repo.StreamTasksByDateRange(ctx, from, to, func(task models.Task) error {
+			ids = append(ids, task.ID)
+			if task.ID == 2 {
+				return assert.AnError
+			}
+			return nil
+		})
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, []int{1, 2}, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - query error", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskQueryRepository(mock, repoMetrics)
+
+		mock.ExpectQuery(`WHERE t.creation_date >= \$1`).
+			WithArgs(from, to).
+			WillReturnError(assert.AnError)
+
+		called := false
+		err = repo.StreamTasksByDateRange(ctx, from, to, func(models.Task) error {
+			called = true
+			return nil
+		})
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.False(t, called)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestStreamTasksByExecutor checks the executor filter and per-row callback.
+func TestStreamTasksByExecutor(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := repository.NewTaskQueryRepository(mock, repoMetrics)
+	created := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`WHERE te.task_id = t.task_id AND e.shortname = \$1`).
+		WithArgs("Doe J.").
+		WillReturnRows(pgxmock.NewRows(taskColumns).
+			AddRow(7, "Repair", created, time.Time{}, "desc", "addr", "John", "john1", []string{}, false,
+				[]string{"Doe J."}))
+
+	var tasks []models.Task
+	err = repo.StreamTasksByExecutor(ctx, "Doe J.", func(task models.Task) error {
+		tasks = append(tasks, task)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, 7, tasks[0].ID)
+	assert.Equal(t, []string{"Doe J."}, tasks[0].Executors)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,6 +3,7 @@ package repository_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/UnknownOlympus/hephaestus/internal/models"
 	"github.com/UnknownOlympus/hephaestus/internal/repository"
@@ -59,13 +60,8 @@ func TestGetOrCreateTaskTypeID(t *testing.T) {
 			WithArgs(typeName).
 			WillReturnError(pgx.ErrNoRows)
 
-		// 2. Waiting for INSERT to create a new type
-		mock.ExpectExec("INSERT INTO task_types").
-			WithArgs(typeName).
-			WillReturnResult(pgxmock.NewResult("INSERT", 1))
-
-		// 3. We are waiting for the second SELECT, which will now find the created ID
-		mock.ExpectQuery("SELECT type_id FROM task_types WHERE type_name = \\$1").
+		// 2. Waiting for INSERT that creates the type and returns its ID
+		mock.ExpectQuery("INSERT INTO task_types .* RETURNING type_id").
 			WithArgs(typeName).
 			WillReturnRows(pgxmock.NewRows([]string{"type_id"}).AddRow(expectedID))
 
@@ -76,53 +72,55 @@ func TestGetOrCreateTaskTypeID(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("failure - db error on select", func(t *testing.T) {
+	t.Run("success - type inserted concurrently", func(t *testing.T) {
 		t.Parallel()
 		mock, err := pgxmock.NewPool()
 		require.NoError(t, err)
 		defer mock.Close()
 
 		repo := repository.NewTaskRepository(mock, repoMetrics)
-		dbError := errors.New("DB error")
 
+		typeName := "Racing Type"
+		existingID := 3
+
+		// 1. Our SELECT does not see the type yet
 		mock.ExpectQuery("SELECT type_id FROM task_types WHERE type_name = \\$1").
-			WithArgs("any type").
-			WillReturnError(dbError)
+			WithArgs(typeName).
+			WillReturnError(pgx.ErrNoRows)
 
-		_, err = repo.GetOrCreateTaskTypeID(ctx, "any type")
+		// 2. Another transaction inserted it meanwhile: the conflicting INSERT still returns the existing ID
+		mock.ExpectQuery("INSERT INTO task_types .* ON CONFLICT \\(type_name\\) DO UPDATE .* RETURNING type_id").
+			WithArgs(typeName).
+			WillReturnRows(pgxmock.NewRows([]string{"type_id"}).AddRow(existingID))
 
-		require.Error(t, err)
-		require.ErrorIs(t, err, dbError)
+		id, err := repo.GetOrCreateTaskTypeID(ctx, typeName)
+
+		require.NoError(t, err)
+		assert.Equal(t, existingID, id)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("failure - insert error", func(t *testing.T) {
+	t.Run("failure - db error on select", func(t *testing.T) {
 		t.Parallel()
 		mock, err := pgxmock.NewPool()
 		require.NoError(t, err)
 		defer mock.Close()
 
-		typeName := "New Type"
 		repo := repository.NewTaskRepository(mock, repoMetrics)
+		dbError := errors.New("DB error")
 
-		// 1. We are expecting a SELECT that will return a "no rows" error.
 		mock.ExpectQuery("SELECT type_id FROM task_types WHERE type_name = \\$1").
-			WithArgs(typeName).
-			WillReturnError(pgx.ErrNoRows)
-
-		// 2. Waiting for INSERT to create a new type
-		mock.ExpectExec("INSERT INTO task_types").
-			WithArgs(typeName).
-			WillReturnError(assert.AnError)
+			WithArgs("any type").
+			WillReturnError(dbError)
 
-		_, err = repo.GetOrCreateTaskTypeID(ctx, typeName)
+		_, err = repo.GetOrCreateTaskTypeID(ctx, "any type")
 
 		require.Error(t, err)
-		require.ErrorIs(t, err, assert.AnError)
+		require.ErrorIs(t, err, dbError)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("failure - 2nd select error", func(t *testing.T) {
+	t.Run("failure - insert error", func(t *testing.T) {
 		t.Parallel()
 		mock, err := pgxmock.NewPool()
 		require.NoError(t, err)
@@ -137,12 +135,7 @@ func TestGetOrCreateTaskTypeID(t *testing.T) {
 			WillReturnError(pgx.ErrNoRows)
 
 		// 2. Waiting for INSERT to create a new type
-		mock.ExpectExec("INSERT INTO task_types").
-			WithArgs(typeName).
-			WillReturnResult(pgxmock.NewResult("INSERT", 1))
-
-		// 3. We are waiting for the second SELECT, which will now find the created ID
-		mock.ExpectQuery("SELECT type_id FROM task_types WHERE type_name = \\$1").
+		mock.ExpectQuery("INSERT INTO task_types").
 			WithArgs(typeName).
 			WillReturnError(assert.AnError)
 
@@ -171,7 +164,8 @@ func TestUpsertTask(t *testing.T) {
 
 		// 2. Waiting for INSERT
 		mock.ExpectExec("INSERT INTO tasks").
-			WithArgs(task.ID, typeID, task.CreatedAt, task.ClosedAt, task.Description, task.Address, task.CustomerName, task.CustomerLogin, task.Comments, false).
+			WithArgs(task.ID, typeID, task.CreatedAt, task.ClosedAt, task.Description, task.Address, task.CustomerName, task.CustomerLogin, task.Comments, false,
+				repository.TaskContentHash(task, typeID)).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		err = repo.UpsertTask(ctx, task, typeID)
@@ -189,7 +183,8 @@ func TestUpsertTask(t *testing.T) {
 		repo := repository.NewTaskRepository(mock, repoMetrics)
 
 		mock.ExpectExec("INSERT INTO tasks").
-			WithArgs(task.ID, typeID, task.CreatedAt, task.ClosedAt, task.Description, task.Address, task.CustomerName, task.CustomerLogin, task.Comments, false).
+			WithArgs(task.ID, typeID, task.CreatedAt, task.ClosedAt, task.Description, task.Address, task.CustomerName, task.CustomerLogin, task.Comments, false,
+				repository.TaskContentHash(task, typeID)).
 			WillReturnError(assert.AnError)
 
 		err = repo.UpsertTask(ctx, task, typeID)
@@ -201,6 +196,47 @@ func TestUpsertTask(t *testing.T) {
 }
 
 // TestUpdateTaskExecutors checks for updates to task executors.
+func TestGetTaskClosedStates(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	query := "SELECT task_id, is_closed FROM tasks WHERE task_id = ANY\\(\\$1\\)"
+
+	t.Run("success - stored tasks only", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+
+		mock.ExpectQuery(query).
+			WithArgs([]int{1, 2, 3}).
+			WillReturnRows(pgxmock.NewRows([]string{"task_id", "is_closed"}).AddRow(1, false).AddRow(3, true))
+
+		states, err := repo.GetTaskClosedStates(ctx, []int{1, 2, 3})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[int]bool{1: false, 3: true}, states)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - on query", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+
+		mock.ExpectQuery(query).WithArgs([]int{1}).WillReturnError(errors.New("db error"))
+
+		_, err = repo.GetTaskClosedStates(ctx, []int{1})
+
+		require.ErrorContains(t, err, "failed to get closed states of tasks")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestUpdateTaskExecutors(t *testing.T) {
 	t.Parallel()
 	ctx := t.Context()
@@ -303,15 +339,15 @@ func TestSaveTaskData(t *testing.T) {
 
 		// Waiting for GetOrCreateTaskTypeID
 		mock.ExpectQuery("SELECT type_id").WithArgs(task.Type).WillReturnError(pgx.ErrNoRows)
-		mock.ExpectExec("INSERT INTO task_types").WithArgs(task.Type).WillReturnResult(pgxmock.NewResult("INSERT", 1))
-		mock.ExpectQuery("SELECT type_id").
+		mock.ExpectQuery("INSERT INTO task_types").
 			WithArgs(task.Type).
 			WillReturnRows(pgxmock.NewRows([]string{"type_id"}).AddRow(typeID))
 
 		// Waiting for UpsertTask (assuming it's a new task)
 		mock.ExpectExec("INSERT INTO tasks").
 			WithArgs(task.ID, typeID, task.CreatedAt, task.ClosedAt, task.Description, task.Address, task.CustomerName,
-				task.CustomerLogin, task.Comments, false).
+				task.CustomerLogin, task.Comments, false,
+				repository.TaskContentHash(task, typeID)).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		// Waiting for UpdateTaskExecutors
@@ -320,6 +356,11 @@ func TestSaveTaskData(t *testing.T) {
 			WithArgs(task.ID, task.Executors[0]).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
+		// Waiting for UpdateTaskComments
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM task_comments").WithArgs(task.ID).WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectCommit()
+
 		err = repo.SaveTaskData(ctx, task)
 
 		require.NoError(t, err)
@@ -353,13 +394,13 @@ func TestSaveTaskData(t *testing.T) {
 		defer mock.Close()
 
 		mock.ExpectQuery("SELECT type_id").WithArgs(task.Type).WillReturnError(pgx.ErrNoRows)
-		mock.ExpectExec("INSERT INTO task_types").WithArgs(task.Type).WillReturnResult(pgxmock.NewResult("INSERT", 1))
-		mock.ExpectQuery("SELECT type_id").
+		mock.ExpectQuery("INSERT INTO task_types").
 			WithArgs(task.Type).
 			WillReturnRows(pgxmock.NewRows([]string{"type_id"}).AddRow(typeID))
 		mock.ExpectExec("INSERT INTO tasks").
 			WithArgs(task.ID, typeID, task.CreatedAt, task.ClosedAt, task.Description, task.Address, task.CustomerName,
-				task.CustomerLogin, task.Comments, false).
+				task.CustomerLogin, task.Comments, false,
+				repository.TaskContentHash(task, typeID)).
 			WillReturnError(assert.AnError)
 
 		repo := repository.NewTaskRepository(mock, repoMetrics)
@@ -377,14 +418,14 @@ func TestSaveTaskData(t *testing.T) {
 		defer mock.Close()
 
 		mock.ExpectQuery("SELECT type_id").WithArgs(task.Type).WillReturnError(pgx.ErrNoRows)
-		mock.ExpectExec("INSERT INTO task_types").WithArgs(task.Type).WillReturnResult(pgxmock.NewResult("INSERT", 1))
-		mock.ExpectQuery("SELECT type_id").
+		mock.ExpectQuery("INSERT INTO task_types").
 			WithArgs(task.Type).
 			WillReturnRows(pgxmock.NewRows([]string{"type_id"}).AddRow(typeID))
 
 		mock.ExpectExec("INSERT INTO tasks").
 			WithArgs(task.ID, typeID, task.CreatedAt, task.ClosedAt, task.Description, task.Address, task.CustomerName,
-				task.CustomerLogin, task.Comments, false).
+				task.CustomerLogin, task.Comments, false,
+				repository.TaskContentHash(task, typeID)).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		mock.ExpectExec("DELETE FROM task_executors").WithArgs(task.ID).WillReturnError(assert.AnError)
@@ -398,3 +439,184 @@ func TestSaveTaskData(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+// TestBackfillTaskContentHashes checks that hashes are computed batch by batch until no rows are left.
+func TestBackfillTaskContentHashes(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	columns := []string{
+		"task_id", "task_type_id", "creation_date", "closing_date", "description",
+		"address", "customer_name", "customer_login", "comments", "is_closed",
+	}
+	created := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	closed := time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)
+
+	t.Run("success - iterates batches", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+		first := models.Task{ID: 1, CreatedAt: created, ClosedAt: closed, Description: "one", IsClosed: true}
+		second := models.Task{ID: 2, CreatedAt: created, Description: "two", Comments: []string{"c"}}
+		third := models.Task{ID: 3, CreatedAt: created, Address: "street"}
+
+		// 1. The first batch is full, so another one is requested
+		mock.ExpectQuery("SELECT task_id, task_type_id").
+			WithArgs(2).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(first.ID, 7, first.CreatedAt, first.ClosedAt, first.Description, "", "", "", []string(nil), true).
+				AddRow(second.ID, 8, second.CreatedAt, time.Time{}, second.Description, "", "", "", second.Comments,
+					false))
+		mock.ExpectExec("UPDATE tasks SET content_hash").
+			WithArgs(first.ID, repository.TaskContentHash(first, 7)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec("UPDATE tasks SET content_hash").
+			WithArgs(second.ID, repository.TaskContentHash(second, 8)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		// 2. The second batch is partial, which means we are done
+		mock.ExpectQuery("SELECT task_id, task_type_id").
+			WithArgs(2).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(third.ID, 7, third.CreatedAt, time.Time{}, "", third.Address, "", "", []string(nil), false))
+		mock.ExpectExec("UPDATE tasks SET content_hash").
+			WithArgs(third.ID, repository.TaskContentHash(third, 7)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		count, err := repo.BackfillTaskContentHashes(ctx, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success - nothing left to backfill", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+
+		mock.ExpectQuery("SELECT task_id, task_type_id").
+			WithArgs(100).
+			WillReturnRows(pgxmock.NewRows(columns))
+
+		count, err := repo.BackfillTaskContentHashes(ctx, 100)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - on update", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+
+		mock.ExpectQuery("SELECT task_id, task_type_id").
+			WithArgs(10).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(1, 7, created, closed, "", "", "", "", []string(nil), true))
+		mock.ExpectExec("UPDATE tasks SET content_hash").
+			WithArgs(1, pgxmock.AnyArg()).
+			WillReturnError(assert.AnError)
+
+		count, err := repo.BackfillTaskContentHashes(ctx, 10)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 0, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - invalid batch size", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+
+		_, err = repo.BackfillTaskContentHashes(ctx, 0)
+
+		require.ErrorContains(t, err, "invalid batch size")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateTaskComments(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	taskID := 101
+	comments := []string{"customer called", "cable replaced"}
+
+	t.Run("success - delete then insert in order", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM task_comments").WithArgs(taskID).WillReturnResult(pgxmock.NewResult("DELETE", 3))
+		mock.ExpectExec("INSERT INTO task_comments").
+			WithArgs(taskID, 1, comments[0]).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO task_comments").
+			WithArgs(taskID, 2, comments[1]).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		err = repo.UpdateTaskComments(ctx, taskID, comments)
+
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - insert error rolls back", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM task_comments").WithArgs(taskID).WillReturnResult(pgxmock.NewResult("DELETE", 3))
+		mock.ExpectExec("INSERT INTO task_comments").
+			WithArgs(taskID, 1, comments[0]).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec("INSERT INTO task_comments").
+			WithArgs(taskID, 2, comments[1]).
+			WillReturnError(assert.AnError)
+		mock.ExpectRollback()
+
+		err = repo.UpdateTaskComments(ctx, taskID, comments)
+
+		require.ErrorIs(t, err, assert.AnError)
+		require.ErrorContains(t, err, "failed to save comment 2 of the task '101'")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure - begin error", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := repository.NewTaskRepository(mock, repoMetrics)
+
+		mock.ExpectBegin().WillReturnError(assert.AnError)
+
+		err = repo.UpdateTaskComments(ctx, taskID, comments)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
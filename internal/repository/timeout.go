@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// queryTimeout wraps a Database and bounds every query with a timeout, so that a hung
+// connection cannot block the caller for longer than that.
+type queryTimeout struct {
+	db      Database
+	timeout time.Duration
+}
+
+// WithQueryTimeout returns db with every Exec, Query and QueryRow bounded by timeout, and every
+// transaction bounded by it as a whole. The caller's own deadline still applies if it is shorter.
+// A zero or negative timeout returns db unchanged.
+func WithQueryTimeout(db Database, timeout time.Duration) Database {
+	if timeout <= 0 {
+		return db
+	}
+
+	return queryTimeout{db: db, timeout: timeout}
+}
+
+// unboundedKey marks a context whose queries are not bounded by WithQueryTimeout.
+type unboundedKey struct{}
+
+// withoutQueryTimeout returns ctx with the queries run under it exempt from WithQueryTimeout.
+// Streams use it, as their rows are read as slowly as the caller consumes them: only the
+// caller's own deadline applies to them.
+func withoutQueryTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unboundedKey{}, true)
+}
+
+// bound returns ctx with the query timeout applied, unless ctx is exempt from it.
+func (q queryTimeout) bound(ctx context.Context) (context.Context, context.CancelFunc) {
+	if unbounded, _ := ctx.Value(unboundedKey{}).(bool); unbounded {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, q.timeout)
+}
+
+func (q queryTimeout) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	ctx, cancel := q.bound(ctx)
+	defer cancel()
+
+	return q.db.Exec(ctx, sql, arguments...)
+}
+
+func (q queryTimeout) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	ctx, cancel := q.bound(ctx)
+
+	rows, err := q.db.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return rows, err
+	}
+
+	return timeoutRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (q queryTimeout) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	ctx, cancel := q.bound(ctx)
+
+	return timeoutRow{row: q.db.QueryRow(ctx, sql, args...), cancel: cancel}
+}
+
+// Begin bounds the whole transaction, from BEGIN to its commit or rollback, with the timeout.
+func (q queryTimeout) Begin(ctx context.Context) (pgx.Tx, error) {
+	txCtx, cancel := q.bound(ctx)
+
+	tx, err := q.db.Begin(txCtx)
+	if err != nil {
+		cancel()
+		return tx, err
+	}
+
+	deadline, bounded := txCtx.Deadline()
+
+	return timeoutTx{Tx: tx, deadline: deadline, bounded: bounded, cancel: cancel}, nil
+}
+
+// timeoutTx runs the statements of a transaction under the caller's context cut at the deadline
+// of the transaction. pgx only uses the context given to Begin for the BEGIN statement itself.
+type timeoutTx struct {
+	pgx.Tx
+
+	deadline time.Time
+	bounded  bool
+	cancel   context.CancelFunc
+}
+
+// within returns ctx cut at the deadline of the transaction, if it has one.
+func (t timeoutTx) within(ctx context.Context) (context.Context, context.CancelFunc) {
+	if !t.bounded {
+		return ctx, func() {}
+	}
+
+	return context.WithDeadline(ctx, t.deadline)
+}
+
+func (t timeoutTx) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	ctx, cancel := t.within(ctx)
+	defer cancel()
+
+	return t.Tx.Exec(ctx, sql, arguments...)
+}
+
+func (t timeoutTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	ctx, cancel := t.within(ctx)
+
+	rows, err := t.Tx.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return rows, err
+	}
+
+	return timeoutRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (t timeoutTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	ctx, cancel := t.within(ctx)
+
+	return timeoutRow{row: t.Tx.QueryRow(ctx, sql, args...), cancel: cancel}
+}
+
+func (t timeoutTx) Commit(ctx context.Context) error {
+	defer t.cancel()
+
+	ctx, cancel := t.within(ctx)
+	defer cancel()
+
+	return t.Tx.Commit(ctx)
+}
+
+// Rollback releases the transaction even past its deadline: pgx closes the connection then,
+// which rolls the transaction back on the server.
+func (t timeoutTx) Rollback(ctx context.Context) error {
+	defer t.cancel()
+
+	ctx, cancel := t.within(ctx)
+	defer cancel()
+
+	return t.Tx.Rollback(ctx)
+}
+
+// timeoutRows keeps the query context alive until the rows are closed.
+type timeoutRows struct {
+	pgx.Rows
+
+	cancel context.CancelFunc
+}
+
+func (r timeoutRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+// timeoutRow keeps the query context alive until the row is scanned.
+type timeoutRow struct {
+	row    pgx.Row
+	cancel context.CancelFunc
+}
+
+func (r timeoutRow) Scan(dest ...any) error {
+	defer r.cancel()
+
+	return r.row.Scan(dest...)
+}
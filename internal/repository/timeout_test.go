@@ -0,0 +1,231 @@
+package repository_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/models"
+	"github.com/UnknownOlympus/hephaestus/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithQueryTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("failure - exec blocks past the timeout", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(updateEmployeeQuery)).
+			WithArgs(1, "John Doe", "Doe J.", "qa", "j@doe.com", "+1").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1)).
+			WillDelayFor(time.Second)
+
+		repo := repository.NewEmployeeRepository(
+			repository.WithQueryTimeout(mock, 20*time.Millisecond), repoMetrics)
+		startTime := time.Now()
+
+		err = repo.UpdateEmployee(t.Context(), 1, "John Doe", "Doe J.", "qa", "j@doe.com", "+1")
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(startTime), time.Second)
+	})
+
+	t.Run("failure - query row blocks past the timeout", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(getEmployeeByIDQuery)).
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "fullname", "shortname", "position", "email", "phone"})).
+			WillDelayFor(time.Second)
+
+		repo := repository.NewEmployeeRepository(
+			repository.WithQueryTimeout(mock, 20*time.Millisecond), repoMetrics)
+
+		_, err = repo.GetEmployeeByID(t.Context(), 1)
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("failure - transaction runs past the timeout as a whole", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		// Each statement fits in the timeout, the transaction does not.
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM task_comments").
+			WithArgs(101).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1)).
+			WillDelayFor(20 * time.Millisecond)
+		mock.ExpectExec("INSERT INTO task_comments").
+			WithArgs(101, 1, "cable replaced").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1)).
+			WillDelayFor(20 * time.Millisecond)
+		mock.ExpectRollback()
+
+		repo := repository.NewTaskRepository(
+			repository.WithQueryTimeout(mock, 30*time.Millisecond), repoMetrics)
+
+		err = repo.UpdateTaskComments(t.Context(), 101, []string{"cable replaced"})
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("success - transaction within the timeout", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM task_comments").WithArgs(101).WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectExec("INSERT INTO task_comments").
+			WithArgs(101, 1, "cable replaced").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		repo := repository.NewTaskRepository(
+			repository.WithQueryTimeout(mock, time.Minute), repoMetrics)
+
+		err = repo.UpdateTaskComments(t.Context(), 101, []string{"cable replaced"})
+
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success - rows stay readable until closed", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`WHERE t.is_closed = false`).
+			WithArgs(time.Hour).
+			WillReturnRows(pgxmock.NewRows(taskColumns).
+				AddRow(1, "Repair", time.Now(), time.Time{}, "desc", "addr", "John", "john1", []string{}, false,
+					[]string{}))
+
+		repo := repository.NewTaskQueryRepository(
+			repository.WithQueryTimeout(mock, time.Minute), repoMetrics)
+
+		tasks, err := repo.GetStuckTasks(t.Context(), time.Hour)
+
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success - streams outlast the timeout", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		created := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`WHERE t.creation_date >= \$1`).
+			WithArgs(created, created.AddDate(0, 0, 1)).
+			WillReturnRows(pgxmock.NewRows(taskColumns).
+				AddRow(1, "Repair", created, time.Time{}, "first", "addr", "John", "john1", []string{}, false,
+					[]string{}).
+				AddRow(2, "Repair", created, time.Time{}, "second", "addr", "John", "john1", []string{}, false,
+					[]string{}))
+
+		repo := repository.NewTaskQueryRepository(
+			repository.WithQueryTimeout(contextRowsDB{mock}, 20*time.Millisecond), repoMetrics)
+
+		var ids []int
+		err = repo.StreamTasksByDateRange(t.Context(), created, created.AddDate(0, 0, 1),
+			func(task models.Task) error {
+				time.Sleep(50 * time.Millisecond)
+				ids = append(ids, task.ID)
+				return nil
+			})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, ids)
+	})
+
+	t.Run("failure - streams stop at the caller deadline", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`WHERE te.task_id = t.task_id AND e.shortname = \$1`).
+			WithArgs("Doe J.").
+			WillReturnRows(pgxmock.NewRows(taskColumns).
+				AddRow(1, "Repair", time.Now(), time.Time{}, "first", "addr", "John", "john1", []string{}, false,
+					[]string{"Doe J."}).
+				AddRow(2, "Repair", time.Now(), time.Time{}, "second", "addr", "John", "john1", []string{}, false,
+					[]string{"Doe J."}))
+
+		repo := repository.NewTaskQueryRepository(
+			repository.WithQueryTimeout(contextRowsDB{mock}, time.Minute), repoMetrics)
+		ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+		defer cancel()
+
+		var ids []int
+		err = repo.StreamTasksByExecutor(ctx, "Doe J.", func(task models.Task) error {
+			time.Sleep(50 * time.Millisecond)
+			ids = append(ids, task.ID)
+			return nil
+		})
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, []int{1}, ids)
+	})
+
+	t.Run("zero timeout leaves the database unchanged", func(t *testing.T) {
+		t.Parallel()
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		assert.Same(t, mock, repository.WithQueryTimeout(mock, 0))
+	})
+}
+
+// contextRowsDB stops reading rows once the context of their query is done, like a pgx
+// connection does, which pgxmock rows do not.
+type contextRowsDB struct {
+	repository.Database
+}
+
+func (d contextRowsDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := d.Database.Query(ctx, sql, args...)
+	if err != nil {
+		return rows, err
+	}
+
+	return contextRows{Rows: rows, ctx: ctx}, nil
+}
+
+type contextRows struct {
+	pgx.Rows
+
+	ctx context.Context
+}
+
+func (r contextRows) Next() bool {
+	return r.ctx.Err() == nil && r.Rows.Next()
+}
+
+func (r contextRows) Err() error {
+	if err := r.ctx.Err(); err != nil {
+		return err
+	}
+
+	return r.Rows.Err()
+}
@@ -5,69 +5,159 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
+// defaultCheckTimeout bounds each dependency check of the readiness probe.
+const defaultCheckTimeout = 3 * time.Second
+
 type DBPinger interface {
 	Ping(ctx context.Context) error
 }
 
 type HealthChecker struct {
-	db           DBPinger
-	log          *slog.Logger
-	hermesHealth grpc_health_v1.HealthClient
+	db            DBPinger
+	log           *slog.Logger
+	hermesHealth  grpc_health_v1.HealthClient
+	safeMode      string
+	shuttingDown  atomic.Bool
+	dbTimeout     time.Duration
+	hermesTimeout time.Duration
 }
 
 func NewHealthChecker(log *slog.Logger, db DBPinger, hermesConn *grpc.ClientConn) *HealthChecker {
 	return &HealthChecker{
-		db:           db,
-		log:          log,
-		hermesHealth: grpc_health_v1.NewHealthClient(hermesConn),
+		db:            db,
+		log:           log,
+		hermesHealth:  grpc_health_v1.NewHealthClient(hermesConn),
+		dbTimeout:     defaultCheckTimeout,
+		hermesTimeout: defaultCheckTimeout,
 	}
 }
 
+// SetCheckTimeouts sets how long the readiness check waits for the database and for Hermes.
+// The checks run concurrently, so a readiness request takes at most the longer of the two.
+func (h *HealthChecker) SetCheckTimeouts(db, hermes time.Duration) {
+	h.dbTimeout = db
+	h.hermesTimeout = hermes
+}
+
+// SetSafeMode makes the health check report the service as not ready for the given reason,
+// while the monitoring server itself keeps running.
+func (h *HealthChecker) SetSafeMode(reason string) {
+	h.safeMode = reason
+}
+
+// SetShuttingDown makes the readiness check fail, so the pod is taken out of the endpoints and
+// not sent traffic while it stops. Liveness reports the shutdown as well.
+func (h *HealthChecker) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// LiveHandler returns a handler that only reports whether the process is running.
+// It does not check any dependency, so a degraded database or Hermes never gets the pod restarted.
+func (h *HealthChecker) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		status := map[string]string{"status": "ok"}
+		code := http.StatusOK
+		if h.shuttingDown.Load() {
+			status["status"] = "shutting_down"
+			code = http.StatusServiceUnavailable
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(code)
+		if err := json.NewEncoder(writer).Encode(status); err != nil {
+			h.log.ErrorContext(req.Context(), "Failed to write liveness response", "error", err)
+		}
+	})
+}
+
+// ServeHTTP performs the readiness check of the database and Hermes. Both dependencies are
+// checked concurrently, each with its own timeout. Once shutting down the service is not ready,
+// and the dependencies are not checked.
 func (h *HealthChecker) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	if h.shuttingDown.Load() {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusServiceUnavailable)
+		if err := json.NewEncoder(writer).Encode(map[string]string{"status": "shutting_down"}); err != nil {
+			h.log.ErrorContext(req.Context(), "Failed to write health check response", "error", err)
+		}
+		return
+	}
+
 	h.log.DebugContext(req.Context(), "Performing health checks...")
 
-	var err error
 	status := make(map[string]string)
 	overallStatus := http.StatusOK
 
-	if err = h.db.Ping(req.Context()); err != nil {
-		status["database"] = "unavailable"
+	if h.safeMode != "" {
+		status["safe_mode"] = h.safeMode
 		overallStatus = http.StatusServiceUnavailable
-		h.log.WarnContext(req.Context(), "Health check failed: DB ping", "error", err)
-	} else {
-		status["database"] = "ok"
 	}
 
-	healthReq := &grpc_health_v1.HealthCheckRequest{Service: ""}
-	resp, err := h.hermesHealth.Check(req.Context(), healthReq)
-	switch {
-	case err != nil:
-		status["hermes_service"] = "unreachable"
-		overallStatus = http.StatusServiceUnavailable
-		h.log.WarnContext(req.Context(), "Health check failed: Hermes service unreachable", "error", err)
-	case resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING:
-		status["hermes_service"] = "degraded"
+	var (
+		wg                     sync.WaitGroup
+		dbStatus, hermesStatus string
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		dbStatus = h.checkDB(req.Context())
+	}()
+	go func() {
+		defer wg.Done()
+		hermesStatus = h.checkHermes(req.Context())
+	}()
+	wg.Wait()
+
+	status["database"] = dbStatus
+	status["hermes_service"] = hermesStatus
+	if dbStatus != "ok" || hermesStatus != "ok" {
 		overallStatus = http.StatusServiceUnavailable
-		h.log.WarnContext(
-			req.Context(),
-			"Health check failed: Hermes service is not serving",
-			"status",
-			resp.GetStatus().String(),
-		)
-	default:
-		status["hermes_service"] = "ok"
 	}
 
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(overallStatus)
-	if err = json.NewEncoder(writer).Encode(status); err != nil {
+	if err := json.NewEncoder(writer).Encode(status); err != nil {
 		h.log.ErrorContext(req.Context(), "Failed to write health check response", "error", err)
 	}
 
 	h.log.DebugContext(req.Context(), "Health checks completed", "status", overallStatus)
 }
+
+// checkDB pings the database and returns its status for the readiness response.
+func (h *HealthChecker) checkDB(ctx context.Context) string {
+	ctx, cancel := context.WithTimeout(ctx, h.dbTimeout)
+	defer cancel()
+
+	if err := h.db.Ping(ctx); err != nil {
+		h.log.WarnContext(ctx, "Health check failed: DB ping", "error", err)
+		return "unavailable"
+	}
+
+	return "ok"
+}
+
+// checkHermes queries the gRPC health service of Hermes and returns its status for the readiness response.
+func (h *HealthChecker) checkHermes(ctx context.Context) string {
+	ctx, cancel := context.WithTimeout(ctx, h.hermesTimeout)
+	defer cancel()
+
+	resp, err := h.hermesHealth.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: ""})
+	switch {
+	case err != nil:
+		h.log.WarnContext(ctx, "Health check failed: Hermes service unreachable", "error", err)
+		return "unreachable"
+	case resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING:
+		h.log.WarnContext(ctx, "Health check failed: Hermes service is not serving", "status", resp.GetStatus().String())
+		return "degraded"
+	default:
+		return "ok"
+	}
+}
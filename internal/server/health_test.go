@@ -9,8 +9,10 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/UnknownOlympus/hephaestus/internal/server"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -30,6 +32,115 @@ func (m *MockDBPinger) Ping(_ context.Context) error {
 	return nil
 }
 
+// slowDBPinger answers a ping after delay, or fails once the context is done.
+type slowDBPinger struct {
+	delay time.Duration
+}
+
+func (p *slowDBPinger) Ping(ctx context.Context) error {
+	select {
+	case <-time.After(p.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// slowHealthServer reports SERVING after delay.
+type slowHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+
+	delay time.Duration
+}
+
+func (s *slowHealthServer) Check(ctx context.Context, _ *grpc_health_v1.HealthCheckRequest,
+) (*grpc_health_v1.HealthCheckResponse, error) {
+	select {
+	case <-time.After(s.delay):
+		return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func newSlowHermesConn(t *testing.T, delay time.Duration) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	t.Cleanup(s.Stop)
+	grpc_health_v1.RegisterHealthServer(s, &slowHealthServer{delay: delay})
+	go func() { _ = s.Serve(lis) }()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestHealthChecker_ConcurrentChecks(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	const delay = 300 * time.Millisecond
+
+	t.Run("slow dependencies take the longest check, not the sum", func(t *testing.T) {
+		t.Parallel()
+
+		healthChecker := server.NewHealthChecker(logger, &slowDBPinger{delay: delay}, newSlowHermesConn(t, delay))
+		rr := httptest.NewRecorder()
+
+		start := time.Now()
+		healthChecker.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		elapsed := time.Since(start)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.JSONEq(t, `{"database":"ok", "hermes_service":"ok"}`, rr.Body.String())
+		require.GreaterOrEqual(t, elapsed, delay)
+		require.Less(t, elapsed, 2*delay)
+	})
+
+	t.Run("each check has its own timeout", func(t *testing.T) {
+		t.Parallel()
+
+		healthChecker := server.NewHealthChecker(logger, &slowDBPinger{delay: time.Hour},
+			newSlowHermesConn(t, time.Hour))
+		healthChecker.SetCheckTimeouts(delay, delay)
+		rr := httptest.NewRecorder()
+
+		start := time.Now()
+		healthChecker.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		elapsed := time.Since(start)
+
+		require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		require.JSONEq(t, `{"database":"unavailable", "hermes_service":"unreachable"}`, rr.Body.String())
+		require.GreaterOrEqual(t, elapsed, delay)
+		require.Less(t, elapsed, 2*delay)
+	})
+}
+
+func TestHealthChecker_RequestCancellation(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// Both dependencies would answer long after the default check timeout.
+	healthChecker := server.NewHealthChecker(logger, &slowDBPinger{delay: time.Hour}, newSlowHermesConn(t, time.Hour))
+	ctx, cancel := context.WithCancel(t.Context())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	rr := httptest.NewRecorder()
+
+	start := time.Now()
+	healthChecker.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil).WithContext(ctx))
+
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	require.JSONEq(t, `{"database":"unavailable", "hermes_service":"unreachable"}`, rr.Body.String())
+}
+
 func TestHealthChecker(t *testing.T) {
 	t.Parallel()
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
@@ -148,4 +259,134 @@ func TestHealthChecker(t *testing.T) {
 		expectedBody := `{"database":"ok", "hermes_service":"unreachable"}`
 		require.JSONEq(t, expectedBody, rr.Body.String())
 	})
+
+	t.Run("safe mode reports the reason", func(t *testing.T) {
+		t.Parallel()
+
+		lis := bufconn.Listen(1024 * 1024)
+		s := grpc.NewServer()
+		defer s.GracefulStop()
+		healthSrv := health.NewServer()
+		healthSrv.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+		grpc_health_v1.RegisterHealthServer(s, healthSrv)
+		go func() { _ = s.Serve(lis) }()
+
+		conn, err := grpc.NewClient(
+			"passthrough:///bufnet",
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		mockDB := &MockDBPinger{ShouldFail: false}
+		healthChecker := server.NewHealthChecker(logger, mockDB, conn)
+		healthChecker.SetSafeMode("database schema mismatch: missing columns tasks.content_hash")
+		req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+		rr := httptest.NewRecorder()
+		healthChecker.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		expectedBody := `{"database":"ok", "hermes_service":"ok",` +
+			` "safe_mode":"database schema mismatch: missing columns tasks.content_hash"}`
+		require.JSONEq(t, expectedBody, rr.Body.String())
+	})
+}
+
+func newHermesConn(t *testing.T, status grpc_health_v1.HealthCheckResponse_ServingStatus) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	t.Cleanup(s.GracefulStop)
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("", status)
+	grpc_health_v1.RegisterHealthServer(s, healthSrv)
+	go func() { _ = s.Serve(lis) }()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestRouter_LivenessAndReadiness(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	tests := []struct {
+		name       string
+		dbFails    bool
+		hermes     grpc_health_v1.HealthCheckResponse_ServingStatus
+		wantLive   int
+		wantReady  int
+		wantStatus string
+	}{
+		{
+			name:       "healthy dependencies",
+			hermes:     grpc_health_v1.HealthCheckResponse_SERVING,
+			wantLive:   http.StatusOK,
+			wantReady:  http.StatusOK,
+			wantStatus: `{"database":"ok", "hermes_service":"ok"}`,
+		},
+		{
+			name:       "database unavailable",
+			dbFails:    true,
+			hermes:     grpc_health_v1.HealthCheckResponse_SERVING,
+			wantLive:   http.StatusOK,
+			wantReady:  http.StatusServiceUnavailable,
+			wantStatus: `{"database":"unavailable", "hermes_service":"ok"}`,
+		},
+		{
+			name:       "hermes degraded",
+			hermes:     grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+			wantLive:   http.StatusOK,
+			wantReady:  http.StatusServiceUnavailable,
+			wantStatus: `{"database":"ok", "hermes_service":"degraded"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			healthChecker := server.NewHealthChecker(logger, &MockDBPinger{ShouldFail: tt.dbFails},
+				newHermesConn(t, tt.hermes))
+			router := server.NewRouter(healthChecker, newStatusHandler(t), prometheus.NewRegistry(), false)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/livez", nil))
+			require.Equal(t, tt.wantLive, rr.Code)
+			require.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+
+			for _, path := range []string{"/readyz", "/healthz"} {
+				rr = httptest.NewRecorder()
+				router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+				require.Equal(t, tt.wantReady, rr.Code, path)
+				require.JSONEq(t, tt.wantStatus, rr.Body.String(), path)
+			}
+		})
+	}
+
+	t.Run("liveness and readiness fail while shutting down", func(t *testing.T) {
+		t.Parallel()
+
+		healthChecker := server.NewHealthChecker(logger, &MockDBPinger{},
+			newHermesConn(t, grpc_health_v1.HealthCheckResponse_SERVING))
+		healthChecker.SetShuttingDown()
+		router := server.NewRouter(healthChecker, newStatusHandler(t), prometheus.NewRegistry(), false)
+
+		for _, path := range []string{"/livez", "/readyz", "/healthz"} {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+
+			require.Equal(t, http.StatusServiceUnavailable, rr.Code, path)
+			require.JSONEq(t, `{"status":"shutting_down"}`, rr.Body.String(), path)
+		}
+	})
 }
@@ -2,17 +2,23 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/http/pprof"
 	"time"
 
+	"github.com/UnknownOlympus/hephaestus/internal/buildinfo"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 )
 
+// shutdownTimeout bounds how long in-flight requests are given to finish once the context is cancelled.
+const shutdownTimeout = 5 * time.Second
+
 // StartMonitoringServer starts an HTTP server that provides health check and metrics endpoints.
 // It listens on the specified port and logs the server's status and any errors encountered.
 //
@@ -22,6 +28,10 @@ import (
 // - reg: A registry with Prometheus collectors.
 // - dtb: A pgxpool connector for database methods (ping)
 // - port: The port number on which the server will listen.
+// - hermesConn: A gRPC connection to Hermes used for its health check.
+// - safeMode: The reason the service runs in safe mode, reported by the health check; empty if it does not.
+// - status: The handler of the scrape progress endpoint.
+// - enablePprof: Whether the pprof handlers are registered under /debug/pprof/.
 func StartMonitoringServer(
 	ctx context.Context,
 	log *slog.Logger,
@@ -29,12 +39,13 @@ func StartMonitoringServer(
 	dtb *pgxpool.Pool,
 	port int,
 	hermesConn *grpc.ClientConn,
+	safeMode string,
+	status *StatusHandler,
+	enablePprof bool,
 ) {
-	mux := http.NewServeMux()
 	healthChecker := NewHealthChecker(log, dtb, hermesConn)
-
-	mux.Handle("/healthz", healthChecker)
-	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	healthChecker.SetSafeMode(safeMode)
+	mux := NewRouter(healthChecker, status, reg, enablePprof)
 
 	log.InfoContext(ctx, "Starting monitoring server", "port", port)
 
@@ -54,14 +65,53 @@ func StartMonitoringServer(
 	}()
 	select {
 	case <-ctx.Done():
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(readTimeout)*time.Second)
+		healthChecker.SetShuttingDown()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 		defer cancel()
 		log.InfoContext(ctx, "Monitoring server shutting down.")
 		if err = server.Shutdown(shutdownCtx); err != nil {
 			log.ErrorContext(ctx, "Monitoring server failed to shutdown", "error", err)
 			return
 		}
+		log.InfoContext(ctx, "Monitoring server stopped.")
 	case err = <-serverErr:
 		log.ErrorContext(ctx, "Monitoring server failed", "error", err)
 	}
 }
+
+// NewRouter registers the monitoring endpoints: /livez for liveness, /readyz for readiness,
+// /healthz as the readiness alias kept for compatibility, /status for the scrape progress, /version for
+// the build metadata, and /metrics.
+// When enablePprof is set, the profiling handlers are served under /debug/pprof/. They are off by
+// default because they expose process internals; CPU profiles have to fit in the server write timeout,
+// e.g. /debug/pprof/profile?seconds=5.
+func NewRouter(
+	healthChecker *HealthChecker,
+	status *StatusHandler,
+	reg *prometheus.Registry,
+	enablePprof bool,
+) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/livez", healthChecker.LiveHandler())
+	mux.Handle("/readyz", healthChecker)
+	mux.Handle("/healthz", healthChecker)
+	mux.Handle("/status", status)
+	mux.HandleFunc("/version", serveVersion)
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	if enablePprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+
+	return mux
+}
+
+// serveVersion writes the build metadata of the running binary.
+func serveVersion(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(writer).Encode(buildinfo.Get())
+}
@@ -0,0 +1,117 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/buildinfo"
+	"github.com/UnknownOlympus/hephaestus/internal/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestNewRouter_Pprof(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	tests := []struct {
+		name        string
+		enablePprof bool
+		want        int
+	}{
+		{name: "enabled", enablePprof: true, want: http.StatusOK},
+		{name: "disabled by default", enablePprof: false, want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			healthChecker := server.NewHealthChecker(logger, &MockDBPinger{},
+				newHermesConn(t, grpc_health_v1.HealthCheckResponse_SERVING))
+			router := server.NewRouter(healthChecker, newStatusHandler(t), prometheus.NewRegistry(), tt.enablePprof)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+
+			require.Equal(t, tt.want, rr.Code)
+		})
+	}
+}
+
+func TestStartMonitoringServer_GracefulShutdown(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := lis.Addr().(*net.TCPAddr).Port
+	require.NoError(t, lis.Close())
+	livez := fmt.Sprintf("http://127.0.0.1:%d/livez", port)
+	client := &http.Client{Timeout: time.Second}
+	hermesConn := newHermesConn(t, grpc_health_v1.HealthCheckResponse_SERVING)
+	status := newStatusHandler(t)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		server.StartMonitoringServer(ctx, logger, prometheus.NewRegistry(), nil, port, hermesConn, "", status, false)
+	}()
+
+	require.Eventually(t, func() bool {
+		resp, getErr := client.Get(livez)
+		if getErr != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 50*time.Millisecond)
+
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(6 * time.Second): // the shutdown timeout plus some slack
+		t.Fatal("monitoring server did not stop after the context was cancelled")
+	}
+
+	_, err = client.Get(livez)
+	require.Error(t, err)
+}
+
+func TestNewRouter_Version(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	healthChecker := server.NewHealthChecker(logger, &MockDBPinger{},
+		newHermesConn(t, grpc_health_v1.HealthCheckResponse_SERVING))
+	router := server.NewRouter(healthChecker, newStatusHandler(t), prometheus.NewRegistry(), false)
+
+	getVersion := func() string {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/version", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		return rr.Body.String()
+	}
+
+	t.Run("defaults when not injected", func(t *testing.T) {
+		require.JSONEq(t, `{"version":"dev", "commit":"unknown", "build_date":"unknown"}`, getVersion())
+	})
+
+	t.Run("injected build metadata", func(t *testing.T) {
+		version, commit, buildDate := buildinfo.Version, buildinfo.Commit, buildinfo.BuildDate
+		t.Cleanup(func() { buildinfo.Version, buildinfo.Commit, buildinfo.BuildDate = version, commit, buildDate })
+		buildinfo.Version, buildinfo.Commit, buildinfo.BuildDate = "v1.4.0", "3f2a9c1", "2026-10-17T12:00:00Z"
+
+		require.JSONEq(t, `{"version":"v1.4.0", "commit":"3f2a9c1", "build_date":"2026-10-17T12:00:00Z"}`,
+			getVersion())
+	})
+}
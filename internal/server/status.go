@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ProcessedDateGetter reads the date the task catch-up resumes from.
+type ProcessedDateGetter interface {
+	GetLastProcessedDate(ctx context.Context) (time.Time, error)
+}
+
+// ProgressReporter is a service whose scrape progress is shown by the status endpoint.
+type ProgressReporter interface {
+	Progress() (string, time.Time)
+}
+
+// CountsReporter is a ProgressReporter that also keeps running totals, shown as the counts
+// of the service.
+type CountsReporter interface {
+	Counts() map[string]int
+}
+
+// StatusHandler serves a human-readable JSON view of the scrape progress.
+type StatusHandler struct {
+	log        *slog.Logger
+	statusRepo ProcessedDateGetter
+	services   map[string]ProgressReporter
+}
+
+type serviceStatus struct {
+	LastKnownHash     string         `json:"last_known_hash"`
+	LastSuccessfulRun *time.Time     `json:"last_successful_run"`
+	Counts            map[string]int `json:"counts,omitempty"`
+}
+
+type statusResponse struct {
+	LastProcessedDate string                   `json:"last_processed_date"`
+	Services          map[string]serviceStatus `json:"services"`
+}
+
+// NewStatusHandler creates a StatusHandler reporting the processed date from statusRepo and the
+// progress of every service, keyed by the service name.
+func NewStatusHandler(log *slog.Logger, statusRepo ProcessedDateGetter,
+	services map[string]ProgressReporter,
+) *StatusHandler {
+	return &StatusHandler{
+		log:        log,
+		statusRepo: statusRepo,
+		services:   services,
+	}
+}
+
+func (h *StatusHandler) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	resp := statusResponse{Services: make(map[string]serviceStatus, len(h.services))}
+
+	lastDate, err := h.statusRepo.GetLastProcessedDate(req.Context())
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		resp.LastProcessedDate = "unavailable"
+		h.log.WarnContext(req.Context(), "Status: failed to get last processed date", "error", err)
+	default:
+		resp.LastProcessedDate = lastDate.Format("2006-01-02")
+	}
+
+	for name, service := range h.services {
+		hash, lastSuccess := service.Progress()
+		status := serviceStatus{LastKnownHash: hash}
+		if !lastSuccess.IsZero() {
+			utc := lastSuccess.UTC()
+			status.LastSuccessfulRun = &utc
+		}
+		if counter, ok := service.(CountsReporter); ok {
+			status.Counts = counter.Counts()
+		}
+		resp.Services[name] = status
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(writer).Encode(resp); err != nil {
+		h.log.ErrorContext(req.Context(), "Failed to write status response", "error", err)
+	}
+}
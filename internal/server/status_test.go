@@ -0,0 +1,81 @@
+package server_test
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/server"
+	mocks "github.com/UnknownOlympus/hephaestus/mock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProgress struct {
+	hash        string
+	lastSuccess time.Time
+}
+
+func (f fakeProgress) Progress() (string, time.Time) { return f.hash, f.lastSuccess }
+
+type fakeCounts struct {
+	fakeProgress
+
+	counts map[string]int
+}
+
+func (f fakeCounts) Counts() map[string]int { return f.counts }
+
+func newStatusHandler(t *testing.T) *server.StatusHandler {
+	t.Helper()
+
+	return server.NewStatusHandler(slog.New(slog.NewTextHandler(os.Stdout, nil)), mocks.NewStatusRepoIface(t), nil)
+}
+
+func TestStatusHandler(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	services := map[string]server.ProgressReporter{
+		"employee": fakeProgress{hash: "emp_hash", lastSuccess: time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)},
+		"task":     fakeCounts{counts: map[string]int{"saved": 3, "skipped": 1}},
+	}
+
+	tests := []struct {
+		name     string
+		date     time.Time
+		err      error
+		wantDate string
+	}{
+		{name: "reports the last processed date", date: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), wantDate: "2024-03-02"},
+		{name: "no date processed yet", err: sql.ErrNoRows, wantDate: ""},
+		{name: "status repository unavailable", err: errors.New("connection refused"), wantDate: "unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStatus := mocks.NewStatusRepoIface(t)
+			mockStatus.On("GetLastProcessedDate", mock.Anything).Return(tt.date, tt.err).Once()
+			handler := server.NewStatusHandler(logger, mockStatus, services)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			require.JSONEq(t, `{
+				"last_processed_date": "`+tt.wantDate+`",
+				"services": {
+					"employee": {"last_known_hash": "emp_hash", "last_successful_run": "2024-03-01T09:30:00Z"},
+					"task": {"last_known_hash": "", "last_successful_run": null, "counts": {"saved": 3, "skipped": 1}}
+				}
+			}`, rr.Body.String())
+		})
+	}
+}
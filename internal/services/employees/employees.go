@@ -8,22 +8,106 @@ import (
 	"log/slog"
 	"net/mail"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/UnknownOlympus/hephaestus/internal/lib/errtrack"
+	"github.com/UnknownOlympus/hephaestus/internal/lib/outage"
 	"github.com/UnknownOlympus/hephaestus/internal/metrics"
 	"github.com/UnknownOlympus/hephaestus/internal/models"
 	"github.com/UnknownOlympus/hephaestus/internal/repository"
 	pb "github.com/UnknownOlympus/olympus-protos/gen/go/scraper/olympus"
 	"github.com/tamathecxder/randomail"
+	"golang.org/x/text/unicode/norm"
 )
 
+const defaultRepeatedErrorLogEvery = 10
+
 type Staff struct {
 	log           *slog.Logger
 	repo          repository.EmployeeRepoIface
 	metrics       *metrics.Metrics
 	hermesClient  pb.ScraperServiceClient
 	lastKnownHash string
+	errTracker    *errtrack.Tracker
+	safeMode      string
+	// failureTolerance is the number of employees that may fail to save in a run without failing it.
+	failureTolerance int
+	quarantine       repository.QuarantineRepoIface
+	now              func() time.Time
+	dbHealth         *outage.Coordinator
+	dataChangedAt    time.Time
+	// deactivateMaxShare is the largest share of active employees that may be deactivated in one run
+	// for missing from Hermes; zero disables deactivation.
+	deactivateMaxShare float64
+	// emailDomain is the domain of generated placeholder emails; empty generates random addresses.
+	emailDomain string
+	// progressMu guards lastKnownHash writes and lastSuccessAt, which are read by Progress.
+	progressMu    sync.Mutex
+	lastSuccessAt time.Time
+}
+
+// Option configures optional Staff behavior.
+type Option func(*Staff)
+
+// WithRepeatedErrorLogEvery sets how often an identical repeated run error is logged again.
+func WithRepeatedErrorLogEvery(every int) Option {
+	return func(s *Staff) {
+		s.errTracker = errtrack.New(every)
+	}
+}
+
+// WithSafeMode puts the service in read-only safe mode for the given reason: Start performs no
+// synchronization and only waits for shutdown. An empty reason leaves the service in normal mode.
+func WithSafeMode(reason string) Option {
+	return func(s *Staff) {
+		s.safeMode = reason
+	}
+}
+
+// WithFailureTolerance lets a run succeed when at most tolerance employees fail to save.
+// Those employees are quarantined: the failures are logged and counted, and the rest of the batch
+// is stored, so a single bad record no longer forces the whole batch to be reprocessed.
+func WithFailureTolerance(tolerance int) Option {
+	return func(s *Staff) {
+		s.failureTolerance = tolerance
+	}
+}
+
+// WithQuarantineStore persists quarantined employees in quarantine, so that the age of the oldest
+// one is exposed as a metric and items stuck in quarantine can be alerted on.
+func WithQuarantineStore(quarantine repository.QuarantineRepoIface) Option {
+	return func(s *Staff) {
+		s.quarantine = quarantine
+	}
+}
+
+// WithDBCoordinator makes the service share the database breaker in coordinator with other
+// services: while a confirmed database outage lasts, runs only probe connectivity and are skipped.
+func WithDBCoordinator(coordinator *outage.Coordinator) Option {
+	return func(s *Staff) {
+		s.dbHealth = coordinator
+	}
+}
+
+// WithDeactivateRemoved marks the employees that Hermes no longer returns as dismissed, unless that
+// would affect more than maxShare of the active employees: such a gap more likely means that Hermes
+// returned a partial list. Dismissed employees that Hermes returns again are reactivated.
+// Zero disables deactivation.
+func WithDeactivateRemoved(maxShare float64) Option {
+	return func(s *Staff) {
+		s.deactivateMaxShare = maxShare
+	}
+}
+
+// WithPlaceholderEmailDomain makes employees without a valid email get a placeholder address under
+// domain, built from their name as firstname.lastname@domain, so that placeholders are easy to filter.
+func WithPlaceholderEmailDomain(domain string) Option {
+	return func(s *Staff) {
+		s.emailDomain = domain
+	}
 }
 
 func NewStaff(
@@ -31,8 +115,22 @@ func NewStaff(
 	repo repository.EmployeeRepoIface,
 	metrics *metrics.Metrics,
 	hermesClient pb.ScraperServiceClient,
+	opts ...Option,
 ) *Staff {
-	return &Staff{log: log, repo: repo, metrics: metrics, hermesClient: hermesClient}
+	staff := &Staff{
+		log:          log,
+		repo:         repo,
+		metrics:      metrics,
+		hermesClient: hermesClient,
+		errTracker:   errtrack.New(defaultRepeatedErrorLogEvery),
+		now:          time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(staff)
+	}
+
+	return staff
 }
 
 func (s *Staff) initLogger(opn string) *slog.Logger {
@@ -50,12 +148,20 @@ func (s *Staff) Start(ctx context.Context, interval time.Duration) error {
 
 	var err error
 
+	if s.safeMode != "" {
+		log.WarnContext(ctx, "Safe mode is active, synchronization is disabled", "reason", s.safeMode)
+		<-ctx.Done()
+		log.InfoContext(ctx, "Service shutting down.")
+		return nil
+	}
+
 	// 1. Catch-up mode
 	log.InfoContext(ctx, "Starting initial data synchronization")
-	if err = s.ProcessEmployee(ctx); err != nil {
+	if _, err = s.ProcessEmployee(ctx); err != nil {
 		log.ErrorContext(ctx, "Initial run failed", "error", err)
 		return fmt.Errorf("failed during catch-up process: %w", err)
 	}
+	s.updateQuarantineAge(ctx, log)
 
 	// 2. Maintainance mode
 	log.InfoContext(ctx, "Starting maintainance mode", "interval", interval.String())
@@ -66,9 +172,12 @@ func (s *Staff) Start(ctx context.Context, interval time.Duration) error {
 		select {
 		case <-ticker.C:
 			log.InfoContext(ctx, "Periodic check triggered.")
-			if err = s.ProcessEmployee(ctx); err != nil {
-				log.ErrorContext(ctx, "Periodic run failed", "error", err)
+			if _, err = s.ProcessEmployee(ctx); err != nil {
+				s.reportRunError(ctx, log, err)
+			} else {
+				s.resetRunError()
 			}
+			s.updateQuarantineAge(ctx, log)
 		case <-ctx.Done():
 			log.InfoContext(ctx, "Service shutting down.")
 			return nil
@@ -76,14 +185,88 @@ func (s *Staff) Start(ctx context.Context, interval time.Duration) error {
 	}
 }
 
-func (s *Staff) ProcessEmployee(pctx context.Context) error {
+// updateQuarantineAge exposes how long the oldest quarantined employee has been waiting for a fix.
+// It runs every cycle, so the age keeps growing even when Hermes has no new data.
+func (s *Staff) updateQuarantineAge(ctx context.Context, log *slog.Logger) {
+	if s.quarantine == nil {
+		return
+	}
+
+	oldest, err := s.quarantine.GetOldestQuarantinedAt(ctx, "employee")
+	if err != nil {
+		log.WarnContext(ctx, "Failed to get oldest quarantined employee", "error", err)
+		return
+	}
+
+	var age float64
+	if !oldest.IsZero() {
+		age = s.now().Sub(oldest).Seconds()
+	}
+	s.metrics.OldestQuarantinedAge.WithLabelValues("employee").Set(age)
+}
+
+// reportRunError deduplicates repeated identical run errors: it is logged only when it changes
+// or on every Nth repetition, while the repeat count is always exposed as a metric. Runs skipped
+// during a database outage are not logged, the outage coordinator reports the outage itself.
+func (s *Staff) reportRunError(ctx context.Context, log *slog.Logger, err error) {
+	count, report := s.errTracker.Observe(err)
+	s.metrics.RepeatedErrors.WithLabelValues("employee").Set(float64(count))
+
+	if report && !errors.Is(err, outage.ErrPaused) {
+		log.WarnContext(ctx, "Periodic run failed", "error", err, "repeat_count", count)
+		return
+	}
+	log.DebugContext(ctx, "Periodic run failed with the same error", "error", err, "repeat_count", count)
+}
+
+func (s *Staff) resetRunError() {
+	s.errTracker.Reset()
+	s.metrics.RepeatedErrors.WithLabelValues("employee").Set(0)
+}
+
+// Progress returns the last hash received from Hermes and the time of the last successful run,
+// which is zero until a run succeeds. It is safe to call while the service is running.
+func (s *Staff) Progress() (string, time.Time) {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+
+	return s.lastKnownHash, s.lastSuccessAt
+}
+
+// recordProgress stores the last hash received from Hermes and, if the run succeeded, when it did.
+func (s *Staff) recordProgress(hash string, succeeded bool) {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+
+	s.lastKnownHash = hash
+	if succeeded {
+		s.lastSuccessAt = s.now()
+	}
+}
+
+// ProcessSummary counts what a run did with the employees received from Hermes.
+type ProcessSummary struct {
+	Saved   int
+	Updated int
+	Skipped int
+	Failed  int
+}
+
+// ProcessEmployee fetches the employees from Hermes, stores the changed ones and returns what it did
+// with them. The summary is also returned with an error, as far as the run got. The Hermes call is
+// bounded by the call timeout of the Hermes client and every query by the repository query timeout.
+func (s *Staff) ProcessEmployee(ctx context.Context) (ProcessSummary, error) {
 	const opn = "Employee.ProcessEmployee"
 	log := s.initLogger(opn)
 	startTime := time.Now()
 
-	contextTimeout := 10
-	ctx, cancel := context.WithTimeout(pctx, time.Duration(contextTimeout)*time.Second)
-	defer cancel()
+	if s.dbHealth != nil {
+		if err := s.dbHealth.Allow(ctx); err != nil {
+			log.DebugContext(ctx, "Database is still unreachable, skipping run", "error", err)
+			s.metrics.Runs.WithLabelValues("failure").Inc()
+			return ProcessSummary{}, err
+		}
+	}
 
 	resp, err := s.hermesClient.GetEmployees(ctx, &pb.GetEmployeesRequest{
 		KnownHash: s.lastKnownHash,
@@ -91,27 +274,63 @@ func (s *Staff) ProcessEmployee(pctx context.Context) error {
 	if err != nil {
 		s.metrics.Runs.WithLabelValues("failure").Inc()
 		s.metrics.RunDuration.WithLabelValues("employee").Observe(float64(time.Since(startTime).Seconds()))
-		return fmt.Errorf("failed to get employees from Hermes: %w", err)
+		return ProcessSummary{}, fmt.Errorf("failed to get employees from Hermes: %w", err)
 	}
 
 	if len(resp.GetEmployees()) == 0 {
-		log.InfoContext(ctx, "No new employee data. Hashes match.", "hash", resp.GetNewHash())
-		s.lastKnownHash = resp.GetNewHash()
-		return nil
+		if s.lastKnownHash != "" && s.lastKnownHash == resp.GetNewHash() {
+			log.InfoContext(ctx, "No new employee data. Hashes match.", "hash", resp.GetNewHash())
+			s.metrics.SyncResults.WithLabelValues("employee", "unchanged").Inc()
+			s.observeDataChange(false)
+		} else {
+			log.InfoContext(ctx, "Hermes returned no employee data.", "hash", resp.GetNewHash())
+			s.metrics.SyncResults.WithLabelValues("employee", "no_data").Inc()
+		}
+		s.recordProgress(resp.GetNewHash(), false)
+		return ProcessSummary{}, nil
 	}
 
 	log.InfoContext(ctx, "New data received from Hermes. Processing...", "employee_count", len(resp.GetEmployees()))
+	s.metrics.SyncResults.WithLabelValues("employee", "new_data").Inc()
+	s.observeDataChange(true)
 
 	employees := convertPbToModels(resp.GetEmployees())
-	fixedEmployees := fixInvalidEmail(ctx, log, employees, s.metrics)
+	fixedEmployees := fixInvalidEmail(ctx, log, employees, s.metrics, s.emailDomain)
+	fixedEmployees = fixInvalidPhone(ctx, log, fixedEmployees, s.metrics)
 
+	activeIDs, err := s.activeEmployeeIDs(ctx)
+	if err != nil {
+		s.recordDBResult(err)
+		return ProcessSummary{}, err
+	}
+
+	var (
+		summary  ProcessSummary
+		failures []error
+	)
+	quarantined := make(map[int]string)
+	// A failed employee does not abort the batch: the rest is still stored and the failures
+	// are returned together, or quarantined when within the tolerance.
+	recordFailure := func(id int, err, cause error) {
+		log.WarnContext(ctx, "Failed to store employee, continuing with the batch", "employee_id", id, "error", err)
+		s.metrics.EmployeesFailed.Inc()
+		summary.Failed++
+		failures = append(failures, err)
+		quarantined[id] = cause.Error()
+	}
 	for _, employee := range fixedEmployees {
 		existed, existedEmployee := IsEmployeeExists(ctx, employee.ID, s.repo)
 		if existed {
-			if existedEmployee == employee {
+			// A dismissed employee that Hermes returns again is updated, which reactivates it.
+			_, active := activeIDs[employee.ID]
+			if (activeIDs == nil || active) && employeesEqual(existedEmployee, employee) {
 				log.DebugContext(ctx, "employee is existed, skipped", "fullname", employee.FullName)
+				summary.Skipped++
 				continue
 			}
+			if strings.TrimSpace(employee.ShortName) == "" {
+				employee.ShortName = existedEmployee.ShortName
+			}
 			updateErr := s.repo.UpdateEmployee(ctx,
 				employee.ID,
 				employee.FullName,
@@ -121,36 +340,93 @@ func (s *Staff) ProcessEmployee(pctx context.Context) error {
 				employee.Phone,
 			)
 			if updateErr != nil {
-				return fmt.Errorf("failed to update employee: '%s': %w", employee.FullName, updateErr)
+				recordFailure(employee.ID,
+					fmt.Errorf("failed to update employee: '%s': %w", employee.FullName, updateErr), updateErr)
+				continue
 			}
+			summary.Updated++
 		} else {
 			saveErr := s.repo.SaveEmployee(ctx, employee.ID, employee.FullName, employee.ShortName,
 				employee.Position, employee.Email, employee.Phone)
 			if saveErr != nil {
-				return fmt.Errorf("failed to save new employee %s: %w", employee.FullName, saveErr)
+				recordFailure(employee.ID,
+					fmt.Errorf("failed to save new employee %s: %w", employee.FullName, saveErr), saveErr)
+				continue
 			}
+			summary.Saved++
 		}
 	}
 
-	s.lastKnownHash = resp.GetNewHash()
+	log.InfoContext(ctx, "Processed employee batch", "saved", summary.Saved, "updated", summary.Updated,
+		"skipped", summary.Skipped, "failed", summary.Failed)
+
+	if len(failures) > s.failureTolerance {
+		err = fmt.Errorf("%d of %d employees failed: %w", len(failures), len(fixedEmployees), errors.Join(failures...))
+		s.recordDBResult(err)
+		return summary, err
+	}
+	if s.quarantine != nil {
+		if err = s.quarantine.ReplaceQuarantined(ctx, "employee", quarantined); err != nil {
+			err = fmt.Errorf("failed to store quarantined employees: %w", err)
+			s.recordDBResult(err)
+			return summary, err
+		}
+	}
+	if err = s.deactivateRemoved(ctx, log, activeIDs, employees); err != nil {
+		s.recordDBResult(err)
+		return summary, err
+	}
+	s.recordDBResult(nil)
+	s.metrics.Quarantined.WithLabelValues("employee").Set(float64(len(failures)))
+	if len(failures) > 0 {
+		log.WarnContext(ctx, "Some employees failed to save and were quarantined",
+			"failed", len(failures), "tolerance", s.failureTolerance, "error", errors.Join(failures...))
+	}
+
+	s.recordProgress(resp.GetNewHash(), true)
 	s.metrics.Runs.WithLabelValues("success").Inc()
 	s.metrics.RunDuration.WithLabelValues("employee").Observe(float64(time.Since(startTime).Seconds()))
 	s.metrics.LastSuccessfulRun.WithLabelValues("employee").SetToCurrentTime()
 
 	log.InfoContext(ctx, "Successfully processed and saved employee data.", "new_hash", s.lastKnownHash)
-	return nil
+	return summary, nil
+}
+
+// observeDataChange exposes how long Hermes has been returning the same data. The time is counted
+// from the last changed response, or from the first observation after the service started.
+func (s *Staff) observeDataChange(changed bool) {
+	now := s.now()
+	if changed || s.dataChangedAt.IsZero() {
+		s.dataChangedAt = now
+	}
+	s.metrics.DataUnchangedSeconds.WithLabelValues("employee").Set(now.Sub(s.dataChangedAt).Seconds())
+}
+
+// recordDBResult feeds the outcome of the writes of a run into the shared database breaker.
+func (s *Staff) recordDBResult(err error) {
+	if s.dbHealth == nil {
+		return
+	}
+
+	if err != nil {
+		s.dbHealth.Failure(err)
+		return
+	}
+	s.dbHealth.Success()
 }
 
 func convertPbToModels(pbEmployees []*pb.Employee) []models.Employee {
 	employees := make([]models.Employee, 0, len(pbEmployees))
 	for _, pbe := range pbEmployees {
+		// Strings are normalized to NFC so that differently encoded but equal names
+		// are not seen as changes on every run.
 		emp := models.Employee{
 			ID:        int(pbe.GetId()),
-			FullName:  pbe.GetFullname(),
-			ShortName: pbe.GetShortname(),
-			Position:  pbe.GetPosition(),
-			Email:     pbe.GetEmail(),
-			Phone:     pbe.GetPhone(),
+			FullName:  norm.NFC.String(pbe.GetFullname()),
+			ShortName: norm.NFC.String(pbe.GetShortname()),
+			Position:  norm.NFC.String(pbe.GetPosition()),
+			Email:     norm.NFC.String(pbe.GetEmail()),
+			Phone:     norm.NFC.String(pbe.GetPhone()),
 		}
 		employees = append(employees, emp)
 	}
@@ -162,23 +438,24 @@ func fixInvalidEmail(
 	log *slog.Logger,
 	employees []models.Employee,
 	metrics *metrics.Metrics,
+	domain string,
 ) []models.Employee {
 	var invalidCounter int
 	fixedEmployees := make([]models.Employee, 0, len(employees))
 
 	for _, employee := range employees {
 		if employee.Email == "" {
-			log.DebugContext(ctx, "Email was not specified, generate random email", "employee", employee.FullName)
-			employee.Email = randomail.GenerateRandomEmail()
+			log.DebugContext(ctx, "Email was not specified, generate placeholder email", "employee", employee.FullName)
+			employee.Email = placeholderEmail(employee.FullName, domain)
 			invalidCounter++
 		}
 
 		isEmail, _ := ValidateEmployee(employee.Email, employee.Phone)
 		if !isEmail {
-			log.InfoContext(ctx, "Employee has invalid email, it will be replaced with temporary random email.",
+			log.InfoContext(ctx, "Employee has invalid email, it will be replaced with temporary placeholder email.",
 				"fullname", employee.FullName, "email", employee.Email,
 			)
-			employee.Email = randomail.GenerateRandomEmail()
+			employee.Email = placeholderEmail(employee.FullName, domain)
 			invalidCounter++
 		}
 
@@ -195,6 +472,160 @@ func fixInvalidEmail(
 	return fixedEmployees
 }
 
+// activeEmployeeIDs returns the set of the employees stored as active when deactivation is enabled,
+// and nil otherwise.
+func (s *Staff) activeEmployeeIDs(ctx context.Context) (map[int]struct{}, error) {
+	if s.deactivateMaxShare <= 0 {
+		return nil, nil
+	}
+
+	ids, err := s.repo.GetActiveEmployeeIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active employees: %w", err)
+	}
+
+	active := make(map[int]struct{}, len(ids))
+	for _, id := range ids {
+		active[id] = struct{}{}
+	}
+
+	return active, nil
+}
+
+// deactivateRemoved marks the employees of activeIDs, the ones active before the run, that are
+// missing from received as dismissed. Nothing is deactivated when Hermes returned no employees or
+// when more than deactivateMaxShare of the active employees are missing.
+func (s *Staff) deactivateRemoved(ctx context.Context, log *slog.Logger, activeIDs map[int]struct{},
+	received []models.Employee,
+) error {
+	if activeIDs == nil || len(received) == 0 {
+		return nil
+	}
+
+	receivedIDs := make(map[int]struct{}, len(received))
+	for _, employee := range received {
+		receivedIDs[employee.ID] = struct{}{}
+	}
+	var removed []int
+	for id := range activeIDs {
+		if _, ok := receivedIDs[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	slices.Sort(removed)
+
+	if len(removed) == 0 {
+		return nil
+	}
+	if float64(len(removed)) > s.deactivateMaxShare*float64(len(activeIDs)) {
+		log.WarnContext(ctx, "Too many employees are missing from Hermes, skipping deactivation",
+			"missing", len(removed), "active", len(activeIDs), "max_share", s.deactivateMaxShare)
+		return nil
+	}
+
+	for _, id := range removed {
+		if err := s.repo.MarkEmployeeDismissed(ctx, id); err != nil {
+			return fmt.Errorf("failed to deactivate removed employee %d: %w", id, err)
+		}
+	}
+	log.InfoContext(ctx, "Deactivated employees removed from Hermes", "count", len(removed), "ids", removed)
+
+	return nil
+}
+
+// fixInvalidPhone normalizes the invalid phone numbers of employees, see normalizePhone, and blanks
+// the ones that cannot be normalized. Employees without a phone are left as they are.
+func fixInvalidPhone(
+	ctx context.Context,
+	log *slog.Logger,
+	employees []models.Employee,
+	metrics *metrics.Metrics,
+) []models.Employee {
+	var fixedCounter int
+
+	for i, employee := range employees {
+		if employee.Phone == "" {
+			continue
+		}
+		if _, isPhone := ValidateEmployee(employee.Email, employee.Phone); isPhone {
+			continue
+		}
+
+		normalized, ok := normalizePhone(employee.Phone)
+		if ok {
+			log.DebugContext(ctx, "Employee has invalid phone number, it was normalized.",
+				"fullname", employee.FullName, "phone", employee.Phone, "normalized", normalized)
+		} else {
+			log.InfoContext(ctx, "Employee has invalid phone number, it will be removed.",
+				"fullname", employee.FullName, "phone", employee.Phone)
+		}
+		employees[i].Phone = normalized
+		fixedCounter++
+	}
+
+	if fixedCounter != 0 {
+		log.WarnContext(ctx, "Number of employees with invalid phone numbers. For more information, enable debug mode",
+			"value", fixedCounter)
+		metrics.PhonesFixed.Add(float64(fixedCounter))
+	}
+
+	return employees
+}
+
+// normalizePhone strips everything but the digits and a leading plus sign from phone, so that
+// "(096) 123.45.67" becomes "0961234567". It returns an empty string and false when the result
+// is still not a valid phone number.
+func normalizePhone(phone string) (string, bool) {
+	var builder strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+			builder.WriteRune(r)
+		}
+	}
+
+	normalized := builder.String()
+	if !isValidPhoneNumber(normalized) {
+		return "", false
+	}
+
+	return normalized, true
+}
+
+// placeholderEmail returns the address given to an employee without a valid email. Without a domain
+// it is random. Otherwise it is the ASCII letters and digits of every word of fullName joined by dots
+// under domain, e.g. "José Smith" becomes jose.smith@domain; a name without any of them gets a random
+// local part under domain.
+func placeholderEmail(fullName, domain string) string {
+	if domain == "" {
+		return randomail.GenerateRandomEmail()
+	}
+
+	var parts []string
+	for _, word := range strings.Fields(norm.NFKD.String(strings.ToLower(fullName))) {
+		part := strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				return r
+			}
+			return -1
+		}, word)
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	if len(parts) > 0 {
+		if email := strings.Join(parts, ".") + "@" + domain; isValidEmail(email) {
+			return email
+		}
+	}
+
+	email, err := randomail.GenerateRandomEmailWithCustomDomain(domain)
+	if err != nil {
+		return randomail.GenerateRandomEmail()
+	}
+	return email
+}
+
 // ValidateEmployee validates the email and phone number of an employee.
 func ValidateEmployee(email, phone string) (bool, bool) {
 	var isEmail bool
@@ -211,6 +642,25 @@ func ValidateEmployee(email, phone string) (bool, bool) {
 	return isEmail, isPhone
 }
 
+// employeesEqual reports whether a and b have the same source-derived fields, i.e. the ones
+// received from Hermes. Fields maintained by the service itself are ignored, so that they
+// never cause an update on their own. Text fields are compared without surrounding whitespace,
+// and a short name missing on either side is not a difference, as Hermes does not always send it.
+func employeesEqual(a, b models.Employee) bool {
+	sameText := func(x, y string) bool {
+		return strings.TrimSpace(x) == strings.TrimSpace(y)
+	}
+	sameShortName := sameText(a.ShortName, b.ShortName) ||
+		strings.TrimSpace(a.ShortName) == "" || strings.TrimSpace(b.ShortName) == ""
+
+	return a.ID == b.ID &&
+		sameText(a.FullName, b.FullName) &&
+		sameShortName &&
+		sameText(a.Position, b.Position) &&
+		sameText(a.Email, b.Email) &&
+		sameText(a.Phone, b.Phone)
+}
+
 // IsEmployeeExists checks if an employee with the given ID exists in the repository.
 func IsEmployeeExists(ctx context.Context, employeeID int, repo repository.EmployeeRepoIface) (bool, models.Employee) {
 	employee, err := repo.GetEmployeeByID(ctx, employeeID)
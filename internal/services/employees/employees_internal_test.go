@@ -1,17 +1,24 @@
 package employees
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/UnknownOlympus/hephaestus/internal/metrics"
 	"github.com/UnknownOlympus/hephaestus/internal/models"
+	"github.com/UnknownOlympus/hephaestus/internal/repository/memory"
 	mocks "github.com/UnknownOlympus/hephaestus/mock"
 	pb "github.com/UnknownOlympus/olympus-protos/gen/go/scraper/olympus"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -32,7 +39,7 @@ func TestProcessEmployee(t *testing.T) {
 			Employees: []*pb.Employee{},
 		}, nil).Once()
 
-		err := staffService.ProcessEmployee(t.Context())
+		_, err := staffService.ProcessEmployee(t.Context())
 
 		require.NoError(t, err)
 		mockRepo.AssertNotCalled(t, "GetEmployeeByID")
@@ -53,7 +60,7 @@ func TestProcessEmployee(t *testing.T) {
 			Return(nil).
 			Once()
 
-		err := staffService.ProcessEmployee(t.Context())
+		_, err := staffService.ProcessEmployee(t.Context())
 
 		require.NoError(t, err)
 		mockRepo.AssertExpectations(t)
@@ -74,7 +81,7 @@ func TestProcessEmployee(t *testing.T) {
 			Return(assert.AnError).
 			Once()
 
-		err := staffService.ProcessEmployee(t.Context())
+		_, err := staffService.ProcessEmployee(t.Context())
 
 		require.Error(t, err)
 		require.ErrorContains(t, err, "failed to save new employee")
@@ -97,7 +104,7 @@ func TestProcessEmployee(t *testing.T) {
 			Return(nil).
 			Once()
 
-		err := staffService.ProcessEmployee(t.Context())
+		_, err := staffService.ProcessEmployee(t.Context())
 
 		require.NoError(t, err)
 		mockRepo.AssertExpectations(t)
@@ -119,7 +126,7 @@ func TestProcessEmployee(t *testing.T) {
 			Return(assert.AnError).
 			Once()
 
-		err := staffService.ProcessEmployee(t.Context())
+		_, err := staffService.ProcessEmployee(t.Context())
 
 		require.Error(t, err)
 		require.ErrorContains(t, err, "failed to update employee")
@@ -137,7 +144,7 @@ func TestProcessEmployee(t *testing.T) {
 		}, nil).Once()
 		mockRepo.On("GetEmployeeByID", mock.Anything, 3).Return(identicalEmployeeModel, nil).Once()
 
-		err := staffService.ProcessEmployee(t.Context())
+		_, err := staffService.ProcessEmployee(t.Context())
 
 		require.NoError(t, err)
 		mockRepo.AssertNotCalled(t, "SaveEmployee")
@@ -151,10 +158,553 @@ func TestProcessEmployee(t *testing.T) {
 			(*pb.GetEmployeesResponse)(nil), errors.New("gRPC connection failed"),
 		).Once()
 
-		err := staffService.ProcessEmployee(t.Context())
+		_, err := staffService.ProcessEmployee(t.Context())
 
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to get employees from Hermes")
 		mockRepo.AssertNotCalled(t, "GetEmployeeByID")
 	})
 }
+
+func TestProcessEmployee_EmptyResponses(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mockRepo := mocks.NewEmployeeRepoIface(t)
+	mockHermes := mocks.NewScraperServiceClient(t)
+	testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+	staffService := NewStaff(logger, mockRepo, testMetrics, mockHermes)
+
+	t.Run("empty list with a new hash counts as no data", func(t *testing.T) {
+		mockHermes.On("GetEmployees", mock.Anything, mock.Anything).Return(&pb.GetEmployeesResponse{
+			NewHash: "hash_1",
+		}, nil).Once()
+
+		_, err := staffService.ProcessEmployee(t.Context())
+
+		require.NoError(t, err)
+		assert.InDelta(t, 1, testutil.ToFloat64(testMetrics.SyncResults.WithLabelValues("employee", "no_data")), 0)
+		assert.InDelta(t, 0, testutil.ToFloat64(testMetrics.SyncResults.WithLabelValues("employee", "unchanged")), 0)
+	})
+
+	t.Run("matching hash counts as unchanged", func(t *testing.T) {
+		mockHermes.On("GetEmployees", mock.Anything, mock.Anything).Return(&pb.GetEmployeesResponse{
+			NewHash: "hash_1",
+		}, nil).Once()
+
+		_, err := staffService.ProcessEmployee(t.Context())
+
+		require.NoError(t, err)
+		assert.InDelta(t, 1, testutil.ToFloat64(testMetrics.SyncResults.WithLabelValues("employee", "no_data")), 0)
+		assert.InDelta(t, 1, testutil.ToFloat64(testMetrics.SyncResults.WithLabelValues("employee", "unchanged")), 0)
+		mockRepo.AssertNotCalled(t, "GetEmployeeByID")
+	})
+}
+
+func TestStart_SafeMode(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mockRepo := mocks.NewEmployeeRepoIface(t)
+	mockHermes := mocks.NewScraperServiceClient(t)
+	staffService := NewStaff(logger, mockRepo, metrics.NewMetrics(prometheus.NewRegistry()), mockHermes,
+		WithSafeMode("database schema mismatch"))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err := staffService.Start(ctx, time.Millisecond)
+
+	require.NoError(t, err)
+	mockHermes.AssertNotCalled(t, "GetEmployees")
+	mockRepo.AssertNotCalled(t, "SaveEmployee")
+	mockRepo.AssertNotCalled(t, "UpdateEmployee")
+}
+
+func TestProcessEmployee_MemoryStore(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	store := memory.New()
+	mockHermes := mocks.NewScraperServiceClient(t)
+	staffService := NewStaff(logger, store, metrics.NewMetrics(prometheus.NewRegistry()), mockHermes)
+
+	employee := &pb.Employee{
+		Id: 1, Fullname: "John Doe", Shortname: "Doe J.", Position: "Engineer", Email: "john@doe.com", Phone: "+380501234567",
+	}
+
+	t.Run("saves a new employee", func(t *testing.T) {
+		mockHermes.On("GetEmployees", mock.Anything, mock.Anything).
+			Return(&pb.GetEmployeesResponse{NewHash: "hash_1", Employees: []*pb.Employee{employee}}, nil).Once()
+
+		_, err := staffService.ProcessEmployee(t.Context())
+		require.NoError(t, err)
+
+		stored, ok := store.Employee(1)
+		require.True(t, ok)
+		assert.Equal(t, "Engineer", stored.Position)
+		assert.Equal(t, 1, store.Writes())
+	})
+
+	t.Run("skips an unchanged employee", func(t *testing.T) {
+		mockHermes.On("GetEmployees", mock.Anything, mock.Anything).
+			Return(&pb.GetEmployeesResponse{NewHash: "hash_2", Employees: []*pb.Employee{employee}}, nil).Once()
+
+		_, err := staffService.ProcessEmployee(t.Context())
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, store.Writes())
+	})
+
+	t.Run("updates a changed employee", func(t *testing.T) {
+		changed := &pb.Employee{
+			Id: 1, Fullname: "John Doe", Shortname: "Doe J.", Position: "Lead", Email: "john@doe.com", Phone: "+380501234567",
+		}
+		mockHermes.On("GetEmployees", mock.Anything, mock.Anything).
+			Return(&pb.GetEmployeesResponse{NewHash: "hash_3", Employees: []*pb.Employee{changed}}, nil).Once()
+
+		_, err := staffService.ProcessEmployee(t.Context())
+		require.NoError(t, err)
+
+		stored, _ := store.Employee(1)
+		assert.Equal(t, "Lead", stored.Position)
+		assert.Equal(t, 2, store.Writes())
+	})
+
+	t.Run("keeps the stored short name when Hermes omits it", func(t *testing.T) {
+		changed := &pb.Employee{
+			Id: 1, Fullname: "John Doe", Position: "Director", Email: "john@doe.com", Phone: "+380501234567",
+		}
+		mockHermes.On("GetEmployees", mock.Anything, mock.Anything).
+			Return(&pb.GetEmployeesResponse{NewHash: "hash_4", Employees: []*pb.Employee{changed}}, nil).Once()
+
+		_, err := staffService.ProcessEmployee(t.Context())
+		require.NoError(t, err)
+
+		stored, _ := store.Employee(1)
+		assert.Equal(t, "Director", stored.Position)
+		assert.Equal(t, "Doe J.", stored.ShortName)
+	})
+}
+
+func TestProcessEmployee_FailureTolerance(t *testing.T) {
+	employeesPb := []*pb.Employee{
+		{Id: 1, Fullname: "First", Email: "first@example.com"},
+		{Id: 2, Fullname: "Second", Email: "second@example.com"},
+		{Id: 3, Fullname: "Third", Email: "third@example.com"},
+	}
+
+	tests := []struct {
+		name            string
+		failing         []int
+		wantError       bool
+		wantHash        string
+		wantQuarantined float64
+	}{
+		{name: "below threshold quarantines failures", failing: []int{2}, wantHash: "new_hash", wantQuarantined: 1},
+		{name: "at threshold quarantines failures", failing: []int{1, 3}, wantHash: "new_hash", wantQuarantined: 2},
+		{name: "above threshold fails the run", failing: []int{1, 2, 3}, wantError: true, wantHash: "old_hash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+			mockRepo := mocks.NewEmployeeRepoIface(t)
+			mockHermes := mocks.NewScraperServiceClient(t)
+			testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+			staffService := NewStaff(logger, mockRepo, testMetrics, mockHermes, WithFailureTolerance(2))
+			staffService.lastKnownHash = "old_hash"
+
+			mockHermes.On("GetEmployees", mock.Anything, mock.Anything).
+				Return(&pb.GetEmployeesResponse{NewHash: "new_hash", Employees: employeesPb}, nil).Once()
+			for _, employee := range employeesPb {
+				id := int(employee.GetId())
+				var saveErr error
+				if slices.Contains(tt.failing, id) {
+					saveErr = assert.AnError
+				}
+				mockRepo.On("GetEmployeeByID", mock.Anything, id).Return(models.Employee{}, sql.ErrNoRows).Once()
+				mockRepo.On("SaveEmployee", mock.Anything, id, employee.GetFullname(), "", "", employee.GetEmail(), "").
+					Return(saveErr).Once()
+			}
+
+			_, err := staffService.ProcessEmployee(t.Context())
+
+			if tt.wantError {
+				require.ErrorIs(t, err, assert.AnError)
+				require.ErrorContains(t, err, "3 of 3 employees failed")
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantHash, staffService.lastKnownHash)
+			require.InDelta(t, tt.wantQuarantined,
+				testutil.ToFloat64(testMetrics.Quarantined.WithLabelValues("employee")), 0)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConvertPbToModels_NormalizesUnicode(t *testing.T) {
+	// "Олена Й." spelled with a precomposed "Й" and with "И" + combining breve.
+	composed := "Олена \u0419."
+	decomposed := "Олена \u0418\u0306."
+	require.NotEqual(t, composed, decomposed)
+
+	fromComposed := convertPbToModels([]*pb.Employee{{Id: 1, Fullname: composed, Shortname: composed}})
+	fromDecomposed := convertPbToModels([]*pb.Employee{{Id: 1, Fullname: decomposed, Shortname: decomposed}})
+
+	require.Equal(t, fromComposed, fromDecomposed)
+	require.Equal(t, composed, fromDecomposed[0].FullName)
+}
+
+func TestEmployeesEqual(t *testing.T) {
+	base := models.Employee{
+		ID: 1, FullName: "John Doe", ShortName: "Doe J.", Position: "Engineer", Email: "john@doe.com", Phone: "+1",
+	}
+
+	tests := []struct {
+		name   string
+		modify func(e *models.Employee)
+		equal  bool
+	}{
+		{name: "identical", modify: func(_ *models.Employee) {}, equal: true},
+		{name: "different full name", modify: func(e *models.Employee) { e.FullName = "Jane Doe" }},
+		{name: "different short name", modify: func(e *models.Employee) { e.ShortName = "Doe Ja." }},
+		{name: "different position", modify: func(e *models.Employee) { e.Position = "Lead" }},
+		{name: "different email", modify: func(e *models.Employee) { e.Email = "jane@doe.com" }},
+		{name: "different phone", modify: func(e *models.Employee) { e.Phone = "+2" }},
+		{name: "whitespace-only differences", modify: func(e *models.Employee) {
+			e.FullName = " John Doe "
+			e.Position = "Engineer\t"
+			e.Email = "john@doe.com\n"
+		}, equal: true},
+		{name: "short name absent", modify: func(e *models.Employee) { e.ShortName = "" }, equal: true},
+		{name: "blank short name", modify: func(e *models.Employee) { e.ShortName = "  " }, equal: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := base
+			tt.modify(&other)
+
+			require.Equal(t, tt.equal, employeesEqual(base, other))
+			require.Equal(t, tt.equal, employeesEqual(other, base), "symmetric")
+		})
+	}
+}
+
+func TestQuarantineStore(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("stores quarantined employees", func(t *testing.T) {
+		mockRepo := mocks.NewEmployeeRepoIface(t)
+		mockHermes := mocks.NewScraperServiceClient(t)
+		mockQuarantine := mocks.NewQuarantineRepoIface(t)
+		staffService := NewStaff(logger, mockRepo, metrics.NewMetrics(prometheus.NewRegistry()), mockHermes,
+			WithFailureTolerance(1), WithQuarantineStore(mockQuarantine))
+
+		mockHermes.On("GetEmployees", mock.Anything, mock.Anything).Return(&pb.GetEmployeesResponse{
+			NewHash:   "new_hash",
+			Employees: []*pb.Employee{{Id: 1, Fullname: "First", Email: "first@example.com"}},
+		}, nil).Once()
+		mockRepo.On("GetEmployeeByID", mock.Anything, 1).Return(models.Employee{}, sql.ErrNoRows).Once()
+		mockRepo.On("SaveEmployee", mock.Anything, 1, "First", "", "", "first@example.com", "").
+			Return(assert.AnError).Once()
+		mockQuarantine.On("ReplaceQuarantined", mock.Anything, "employee", map[int]string{1: assert.AnError.Error()}).
+			Return(nil).Once()
+
+		_, err := staffService.ProcessEmployee(t.Context())
+		require.NoError(t, err)
+		mockQuarantine.AssertExpectations(t)
+	})
+
+	t.Run("gauge reflects the oldest item's age", func(t *testing.T) {
+		mockQuarantine := mocks.NewQuarantineRepoIface(t)
+		testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+		staffService := NewStaff(logger, mocks.NewEmployeeRepoIface(t), testMetrics, mocks.NewScraperServiceClient(t),
+			WithQuarantineStore(mockQuarantine))
+		staffService.now = func() time.Time { return now }
+		gauge := testMetrics.OldestQuarantinedAge.WithLabelValues("employee")
+
+		mockQuarantine.On("GetOldestQuarantinedAt", mock.Anything, "employee").
+			Return(now.Add(-2*time.Hour), nil).Once()
+		staffService.updateQuarantineAge(t.Context(), logger)
+		require.InDelta(t, (2 * time.Hour).Seconds(), testutil.ToFloat64(gauge), 0)
+
+		mockQuarantine.On("GetOldestQuarantinedAt", mock.Anything, "employee").Return(time.Time{}, nil).Once()
+		staffService.updateQuarantineAge(t.Context(), logger)
+		require.InDelta(t, 0, testutil.ToFloat64(gauge), 0)
+	})
+}
+
+func TestProcessEmployee_DataUnchangedSeconds(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+	mockHermes := mocks.NewScraperServiceClient(t)
+	staffService := NewStaff(logger, memory.New(), testMetrics, mockHermes)
+	gauge := testMetrics.DataUnchangedSeconds.WithLabelValues("employee")
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	staffService.now = func() time.Time { return now }
+
+	employee := &pb.Employee{Id: 1, Fullname: "John Doe", Email: "john@doe.com"}
+	mockHermes.On("GetEmployees", mock.Anything, mock.Anything).
+		Return(&pb.GetEmployeesResponse{NewHash: "hash_1", Employees: []*pb.Employee{employee}}, nil).Once()
+	mockHermes.On("GetEmployees", mock.Anything, mock.Anything).
+		Return(&pb.GetEmployeesResponse{NewHash: "hash_1"}, nil).Twice()
+
+	_, err := staffService.ProcessEmployee(t.Context())
+	require.NoError(t, err)
+	require.InDelta(t, 0, testutil.ToFloat64(gauge), 0)
+
+	now = now.Add(2 * time.Hour)
+	_, err = staffService.ProcessEmployee(t.Context())
+	require.NoError(t, err)
+	require.InDelta(t, (2 * time.Hour).Seconds(), testutil.ToFloat64(gauge), 0)
+
+	now = now.Add(time.Hour)
+	_, err = staffService.ProcessEmployee(t.Context())
+	require.NoError(t, err)
+	require.InDelta(t, (3 * time.Hour).Seconds(), testutil.ToFloat64(gauge), 0)
+}
+
+func TestProcessEmployee_MidBatchFailure(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mockRepo := mocks.NewEmployeeRepoIface(t)
+	mockHermes := mocks.NewScraperServiceClient(t)
+	testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+	staffService := NewStaff(logger, mockRepo, testMetrics, mockHermes)
+	staffService.lastKnownHash = "old_hash"
+
+	mockHermes.On("GetEmployees", mock.Anything, mock.Anything).
+		Return(&pb.GetEmployeesResponse{NewHash: "new_hash", Employees: []*pb.Employee{
+			{Id: 1, Fullname: "First", Email: "first@example.com"},
+			{Id: 2, Fullname: "Second", Email: "second@example.com"},
+			{Id: 3, Fullname: "Third", Email: "third@example.com"},
+		}}, nil).Once()
+	mockRepo.On("GetEmployeeByID", mock.Anything, mock.AnythingOfType("int")).
+		Return(models.Employee{}, sql.ErrNoRows).Times(3)
+	mockRepo.On("SaveEmployee", mock.Anything, 1, "First", "", "", "first@example.com", "").Return(nil).Once()
+	mockRepo.On("SaveEmployee", mock.Anything, 2, "Second", "", "", "second@example.com", "").
+		Return(assert.AnError).Once()
+	mockRepo.On("SaveEmployee", mock.Anything, 3, "Third", "", "", "third@example.com", "").Return(nil).Once()
+
+	_, err := staffService.ProcessEmployee(t.Context())
+
+	require.ErrorIs(t, err, assert.AnError)
+	require.ErrorContains(t, err, "1 of 3 employees failed")
+	require.Equal(t, "old_hash", staffService.lastKnownHash, "the hash is not advanced while an employee failed")
+	require.InDelta(t, 1, testutil.ToFloat64(testMetrics.EmployeesFailed), 0)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessEmployee_Summary(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mockRepo := mocks.NewEmployeeRepoIface(t)
+	mockHermes := mocks.NewScraperServiceClient(t)
+	staffService := NewStaff(logger, mockRepo, metrics.NewMetrics(prometheus.NewRegistry()), mockHermes,
+		WithFailureTolerance(1))
+
+	mockHermes.On("GetEmployees", mock.Anything, mock.Anything).
+		Return(&pb.GetEmployeesResponse{NewHash: "new_hash", Employees: []*pb.Employee{
+			{Id: 1, Fullname: "New", Email: "new@example.com"},
+			{Id: 2, Fullname: "Same", Email: "same@example.com"},
+			{Id: 3, Fullname: "Changed", Email: "changed@example.com", Position: "Engineer"},
+			{Id: 4, Fullname: "Broken", Email: "broken@example.com"},
+		}}, nil).Once()
+	mockRepo.On("GetEmployeeByID", mock.Anything, 1).Return(models.Employee{}, sql.ErrNoRows).Once()
+	mockRepo.On("GetEmployeeByID", mock.Anything, 2).
+		Return(models.Employee{ID: 2, FullName: "Same", Email: "same@example.com"}, nil).Once()
+	mockRepo.On("GetEmployeeByID", mock.Anything, 3).
+		Return(models.Employee{ID: 3, FullName: "Changed", Email: "changed@example.com"}, nil).Once()
+	mockRepo.On("GetEmployeeByID", mock.Anything, 4).Return(models.Employee{}, sql.ErrNoRows).Once()
+	mockRepo.On("SaveEmployee", mock.Anything, 1, "New", "", "", "new@example.com", "").Return(nil).Once()
+	mockRepo.On("UpdateEmployee", mock.Anything, 3, "Changed", "", "Engineer", "changed@example.com", "").
+		Return(nil).Once()
+	mockRepo.On("SaveEmployee", mock.Anything, 4, "Broken", "", "", "broken@example.com", "").
+		Return(assert.AnError).Once()
+
+	summary, err := staffService.ProcessEmployee(t.Context())
+
+	require.NoError(t, err)
+	require.Equal(t, ProcessSummary{Saved: 1, Updated: 1, Skipped: 1, Failed: 1}, summary)
+
+	t.Run("unchanged data processes nothing", func(t *testing.T) {
+		mockHermes.On("GetEmployees", mock.Anything, mock.Anything).
+			Return(&pb.GetEmployeesResponse{NewHash: "new_hash"}, nil).Once()
+
+		summary, err = staffService.ProcessEmployee(t.Context())
+
+		require.NoError(t, err)
+		require.Equal(t, ProcessSummary{}, summary)
+	})
+}
+
+func TestPlaceholderEmail(t *testing.T) {
+	tests := []struct {
+		name     string
+		fullName string
+		want     string
+	}{
+		{name: "first and last name", fullName: "John Smith", want: "john.smith@noreply.example.com"},
+		{name: "accents are dropped", fullName: "José  Núñez", want: "jose.nunez@noreply.example.com"},
+		{name: "punctuation is dropped", fullName: "Mary-Ann O'Neil", want: "maryann.oneil@noreply.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email := placeholderEmail(tt.fullName, "noreply.example.com")
+
+			require.Equal(t, tt.want, email)
+			require.True(t, isValidEmail(email))
+			require.Equal(t, email, placeholderEmail(tt.fullName, "noreply.example.com"), "deterministic")
+		})
+	}
+
+	t.Run("random local part when the name is empty", func(t *testing.T) {
+		for _, name := range []string{"", "Іван Петренко"} {
+			email := placeholderEmail(name, "noreply.example.com")
+
+			require.True(t, isValidEmail(email))
+			require.True(t, strings.HasSuffix(email, "@noreply.example.com"), email)
+		}
+	})
+
+	t.Run("random address without a domain", func(t *testing.T) {
+		require.True(t, isValidEmail(placeholderEmail("John Smith", "")))
+	})
+}
+
+func TestProcessEmployee_PlaceholderEmailDomain(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mockRepo := mocks.NewEmployeeRepoIface(t)
+	mockHermes := mocks.NewScraperServiceClient(t)
+	testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+	staffService := NewStaff(logger, mockRepo, testMetrics, mockHermes,
+		WithPlaceholderEmailDomain("noreply.example.com"))
+
+	mockHermes.On("GetEmployees", mock.Anything, mock.Anything).
+		Return(&pb.GetEmployeesResponse{NewHash: "new_hash", Employees: []*pb.Employee{
+			{Id: 1, Fullname: "John Smith"},
+			{Id: 2, Fullname: "Jane Doe", Email: "not-an-email"},
+		}}, nil).Once()
+	mockRepo.On("GetEmployeeByID", mock.Anything, mock.AnythingOfType("int")).
+		Return(models.Employee{}, sql.ErrNoRows).Twice()
+	mockRepo.On("SaveEmployee", mock.Anything, 1, "John Smith", "", "", "john.smith@noreply.example.com", "").
+		Return(nil).Once()
+	mockRepo.On("SaveEmployee", mock.Anything, 2, "Jane Doe", "", "", "jane.doe@noreply.example.com", "").
+		Return(nil).Once()
+
+	_, err := staffService.ProcessEmployee(t.Context())
+	require.NoError(t, err)
+
+	require.InDelta(t, 2, testutil.ToFloat64(testMetrics.EmailsFixed), 0)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFixInvalidPhone(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	tests := []struct {
+		name  string
+		phone string
+		want  string
+		fixed float64
+	}{
+		{name: "valid phone is kept", phone: "+380501234567", want: "+380501234567"},
+		{name: "valid phone with spaces is kept", phone: "096 123 45 67", want: "096 123 45 67"},
+		{name: "empty phone is kept", phone: "", want: ""},
+		{name: "invalid phone is normalized", phone: "(096) 123.45.67", want: "0961234567", fixed: 1},
+		{name: "invalid phone is removed", phone: "call the office", want: "", fixed: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+			employees := []models.Employee{{ID: 1, FullName: "John Doe", Email: "john@doe.com", Phone: tt.phone}}
+
+			fixed := fixInvalidPhone(t.Context(), logger, employees, testMetrics)
+
+			require.Len(t, fixed, 1)
+			require.Equal(t, tt.want, fixed[0].Phone)
+			require.InDelta(t, tt.fixed, testutil.ToFloat64(testMetrics.PhonesFixed), 0)
+		})
+	}
+}
+
+func TestProcessEmployee_DeactivateRemoved(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	pbEmployee := func(id int64) *pb.Employee {
+		return &pb.Employee{Id: id, Fullname: fmt.Sprintf("Employee %d", id), Email: fmt.Sprintf("e%d@example.com", id)}
+	}
+	newStaff := func(t *testing.T) (*Staff, *memory.Store, *mocks.ScraperServiceClient) {
+		t.Helper()
+
+		store := memory.New()
+		for id := 1; id <= 4; id++ {
+			require.NoError(t, store.SaveEmployee(t.Context(), id, fmt.Sprintf("Employee %d", id), "", "",
+				fmt.Sprintf("e%d@example.com", id), ""))
+		}
+		mockHermes := mocks.NewScraperServiceClient(t)
+		staff := NewStaff(logger, store, metrics.NewMetrics(prometheus.NewRegistry()), mockHermes,
+			WithDeactivateRemoved(0.5))
+
+		return staff, store, mockHermes
+	}
+
+	t.Run("deactivates employees missing from Hermes", func(t *testing.T) {
+		staff, store, mockHermes := newStaff(t)
+		mockHermes.On("GetEmployees", mock.Anything, mock.Anything).Return(&pb.GetEmployeesResponse{
+			NewHash: "hash_1", Employees: []*pb.Employee{pbEmployee(1), pbEmployee(2), pbEmployee(3)},
+		}, nil).Once()
+
+		_, err := staff.ProcessEmployee(t.Context())
+		require.NoError(t, err)
+
+		assert.True(t, store.Dismissed(4))
+		for id := 1; id <= 3; id++ {
+			assert.False(t, store.Dismissed(id))
+		}
+	})
+
+	t.Run("reactivates employees that return to Hermes", func(t *testing.T) {
+		staff, store, mockHermes := newStaff(t)
+		mockHermes.On("GetEmployees", mock.Anything, mock.Anything).Return(&pb.GetEmployeesResponse{
+			NewHash: "hash_1", Employees: []*pb.Employee{pbEmployee(1), pbEmployee(2), pbEmployee(3)},
+		}, nil).Once()
+		mockHermes.On("GetEmployees", mock.Anything, mock.Anything).Return(&pb.GetEmployeesResponse{
+			NewHash: "hash_2", Employees: []*pb.Employee{pbEmployee(1), pbEmployee(2), pbEmployee(3), pbEmployee(4)},
+		}, nil).Once()
+
+		_, err := staff.ProcessEmployee(t.Context())
+		require.NoError(t, err)
+		require.True(t, store.Dismissed(4))
+
+		summary, err := staff.ProcessEmployee(t.Context())
+		require.NoError(t, err)
+
+		assert.False(t, store.Dismissed(4))
+		assert.Equal(t, ProcessSummary{Updated: 1, Skipped: 3}, summary)
+	})
+
+	t.Run("keeps everyone when too many are missing", func(t *testing.T) {
+		staff, store, mockHermes := newStaff(t)
+		mockHermes.On("GetEmployees", mock.Anything, mock.Anything).Return(&pb.GetEmployeesResponse{
+			NewHash: "hash_1", Employees: []*pb.Employee{pbEmployee(1)},
+		}, nil).Once()
+
+		_, err := staff.ProcessEmployee(t.Context())
+		require.NoError(t, err)
+
+		for id := 1; id <= 4; id++ {
+			assert.False(t, store.Dismissed(id))
+		}
+	})
+
+	t.Run("keeps everyone when Hermes returns no employees", func(t *testing.T) {
+		staff, store, mockHermes := newStaff(t)
+		mockHermes.On("GetEmployees", mock.Anything, mock.Anything).
+			Return(&pb.GetEmployeesResponse{NewHash: "hash_1"}, nil).Once()
+
+		_, err := staff.ProcessEmployee(t.Context())
+		require.NoError(t, err)
+
+		for id := 1; id <= 4; id++ {
+			assert.False(t, store.Dismissed(id))
+		}
+	})
+}
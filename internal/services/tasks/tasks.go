@@ -6,22 +6,178 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
+	"github.com/UnknownOlympus/hephaestus/internal/lib/errtrack"
+	"github.com/UnknownOlympus/hephaestus/internal/lib/outage"
 	"github.com/UnknownOlympus/hephaestus/internal/metrics"
 	"github.com/UnknownOlympus/hephaestus/internal/models"
 	"github.com/UnknownOlympus/hephaestus/internal/repository"
 	pb "github.com/UnknownOlympus/olympus-protos/gen/go/scraper/olympus"
+	"golang.org/x/text/unicode/norm"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
+const defaultRepeatedErrorLogEvery = 10
+
+// ErrDBCircuitOpen is returned when a run is skipped because the database breaker is open
+// and the connectivity probe failed.
+var ErrDBCircuitOpen = outage.ErrPaused
+
+// DBPinger probes database connectivity while the database breaker is open.
+type DBPinger interface {
+	Ping(ctx context.Context) error
+}
+
+// dbError marks errors that come from the database, so that only they count towards the
+// database breaker. It is transparent for error messages and errors.Is/As.
+type dbError struct {
+	err error
+}
+
+func (e dbError) Error() string { return e.err.Error() }
+
+func (e dbError) Unwrap() error { return e.err }
+
+// TaskObserver is notified about changes of the tasks the service stores.
+type TaskObserver interface {
+	// OnTaskClosed is called after a task that was stored as open has been saved as closed.
+	OnTaskClosed(ctx context.Context, task models.Task)
+}
+
 type TaskService struct {
-	log           *slog.Logger
-	repo          repository.TaskRepoIface
-	statusRepo    repository.StatusRepoIface
-	hermesClient  pb.ScraperServiceClient
-	metrics       *metrics.Metrics
-	lastKnownHash string
+	log              *slog.Logger
+	repo             repository.TaskRepoIface
+	statusRepo       repository.StatusRepoIface
+	hermesClient     pb.ScraperServiceClient
+	metrics          *metrics.Metrics
+	lastKnownHash    string
+	catchupStartDate time.Time
+	skipToday        bool
+	errTracker       *errtrack.Tracker
+	now              func() time.Time
+	dbHealth         *outage.Coordinator
+	dateHashes       repository.DateHashRepoIface
+	safeMode         string
+	onlyClosed       bool
+	catchupWorkers   int
+	observer         TaskObserver
+	deadLetter       repository.FailedTaskRepoIface
+	deadLetterAfter  int
+	// syncMu guards dataChangedAt, which parallel catch-up workers update.
+	syncMu        sync.Mutex
+	dataChangedAt time.Time
+	// progressMu guards lastKnownHash and lastSuccessAt, which are read by Progress while the
+	// service is running.
+	progressMu    sync.Mutex
+	lastSuccessAt time.Time
+	// totals accumulates the tasks of every synced date, guarded by progressMu.
+	totals DateSummary
+}
+
+// DateSummary counts what syncing a date did with the tasks received from Hermes. Skipped tasks
+// were not stored because the date was unchanged or because they were filtered out.
+type DateSummary struct {
+	Date    time.Time
+	Saved   int
+	Skipped int
+}
+
+// Option configures optional TaskService behavior.
+type Option func(*TaskService)
+
+// WithCatchupStartDate sets the date from which catch-up starts when no date has been processed yet.
+func WithCatchupStartDate(date time.Time) Option {
+	return func(ts *TaskService) {
+		ts.catchupStartDate = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// WithCatchupSkipToday makes catch-up stop at yesterday, leaving the incomplete current day
+// to the maintenance loop instead of fetching it twice.
+func WithCatchupSkipToday(skip bool) Option {
+	return func(ts *TaskService) {
+		ts.skipToday = skip
+	}
+}
+
+// WithRepeatedErrorLogEvery sets how often an identical repeated run error is logged again.
+func WithRepeatedErrorLogEvery(every int) Option {
+	return func(ts *TaskService) {
+		ts.errTracker = errtrack.New(every)
+	}
+}
+
+// WithDBBreaker enables the database breaker: after threshold consecutive runs failing on the
+// database, writes are paused and every run first probes connectivity through pinger.
+func WithDBBreaker(threshold int, pinger DBPinger) Option {
+	return func(ts *TaskService) {
+		ts.dbHealth = outage.New(ts.log, "database", threshold, pinger,
+			ts.metrics.CircuitState.WithLabelValues("db", "task"))
+	}
+}
+
+// WithDBCoordinator makes the service share the database breaker in coordinator with other
+// services, so that they are paused and resumed together. It replaces WithDBBreaker.
+func WithDBCoordinator(coordinator *outage.Coordinator) Option {
+	return func(ts *TaskService) {
+		ts.dbHealth = coordinator
+	}
+}
+
+// WithDateHashes makes the service keep the Hermes hash of every date in dateHashes instead of a
+// single rolling hash, so that re-processing a date is skipped only if that very date is unchanged.
+func WithDateHashes(dateHashes repository.DateHashRepoIface) Option {
+	return func(ts *TaskService) {
+		ts.dateHashes = dateHashes
+	}
+}
+
+// WithSafeMode puts the service in read-only safe mode for the given reason: Start performs no
+// synchronization and only waits for shutdown. An empty reason leaves the service in normal mode.
+func WithSafeMode(reason string) Option {
+	return func(ts *TaskService) {
+		ts.safeMode = reason
+	}
+}
+
+// WithIngestOnlyClosed makes the service skip tasks that have no closing date yet.
+// Skipped tasks are counted in the filtered items metric.
+func WithIngestOnlyClosed(onlyClosed bool) Option {
+	return func(ts *TaskService) {
+		ts.onlyClosed = onlyClosed
+	}
+}
+
+// WithCatchupWorkers makes catch-up process up to workers days concurrently. The processed-date
+// cursor still only advances over the days that were all stored, so an interrupted catch-up
+// resumes from the first missing day. One or less keeps catch-up sequential.
+func WithCatchupWorkers(workers int) Option {
+	return func(ts *TaskService) {
+		ts.catchupWorkers = workers
+	}
+}
+
+// WithTaskObserver makes the service notify observer when a stored open task gets closed.
+// Tasks seen for the first time are not reported, even if they are already closed.
+func WithTaskObserver(observer TaskObserver) Option {
+	return func(ts *TaskService) {
+		ts.observer = observer
+	}
+}
+
+// WithDeadLetter retries a failing task save up to maxAttempts times within a run, then moves the
+// task to store and continues with the rest of the day, so that a single bad task cannot stall
+// catch-up. Zero disables it.
+func WithDeadLetter(store repository.FailedTaskRepoIface, maxAttempts int) Option {
+	return func(ts *TaskService) {
+		if maxAttempts <= 0 {
+			return
+		}
+		ts.deadLetter = store
+		ts.deadLetterAfter = maxAttempts
+	}
 }
 
 func NewTaskService(log *slog.Logger,
@@ -29,8 +185,24 @@ func NewTaskService(log *slog.Logger,
 	statusRepo repository.StatusRepoIface,
 	metrics *metrics.Metrics,
 	hermesClient pb.ScraperServiceClient,
+	opts ...Option,
 ) *TaskService {
-	return &TaskService{log: log, repo: repo, statusRepo: statusRepo, metrics: metrics, hermesClient: hermesClient}
+	taskService := &TaskService{
+		log:              log,
+		repo:             repo,
+		statusRepo:       statusRepo,
+		metrics:          metrics,
+		hermesClient:     hermesClient,
+		catchupStartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		errTracker:       errtrack.New(defaultRepeatedErrorLogEvery),
+		now:              time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(taskService)
+	}
+
+	return taskService
 }
 
 func (ts *TaskService) initLogger(opn string) *slog.Logger {
@@ -46,6 +218,13 @@ func (ts *TaskService) Start(ctx context.Context, interval time.Duration) error
 
 	var err error
 
+	if ts.safeMode != "" {
+		log.WarnContext(ctx, "Safe mode is active, synchronization is disabled", "reason", ts.safeMode)
+		<-ctx.Done()
+		log.InfoContext(ctx, "Service shutting down.")
+		return nil
+	}
+
 	// 2. Update task types
 	if err = ts.updateTaskTypes(ctx); err != nil {
 		log.ErrorContext(ctx, "failed to update task types on startup", "error", err)
@@ -66,8 +245,10 @@ func (ts *TaskService) Start(ctx context.Context, interval time.Duration) error
 		select {
 		case <-ticker.C:
 			log.InfoContext(ctx, "Periodic check triggered.")
-			if err = ts.processDate(ctx, time.Now()); err != nil {
-				log.ErrorContext(ctx, "Periodic run failed", "error", err)
+			if _, err = ts.processDate(ctx, ts.now()); err != nil {
+				ts.reportRunError(ctx, log, err)
+			} else {
+				ts.resetRunError()
 			}
 		case <-ctx.Done():
 			log.InfoContext(ctx, "Service shutting down.")
@@ -76,40 +257,48 @@ func (ts *TaskService) Start(ctx context.Context, interval time.Duration) error
 	}
 }
 
+// reportRunError deduplicates repeated identical run errors: it is logged only when it changes
+// or on every Nth repetition, while the repeat count is always exposed as a metric. Runs skipped
+// during a database outage are not logged, the outage coordinator reports the outage itself.
+func (ts *TaskService) reportRunError(ctx context.Context, log *slog.Logger, err error) {
+	count, report := ts.errTracker.Observe(err)
+	ts.metrics.RepeatedErrors.WithLabelValues("task").Set(float64(count))
+
+	if report && !errors.Is(err, outage.ErrPaused) {
+		log.WarnContext(ctx, "Periodic run failed", "error", err, "repeat_count", count)
+		return
+	}
+	log.DebugContext(ctx, "Periodic run failed with the same error", "error", err, "repeat_count", count)
+}
+
+func (ts *TaskService) resetRunError() {
+	ts.errTracker.Reset()
+	ts.metrics.RepeatedErrors.WithLabelValues("task").Set(0)
+}
+
 func (ts *TaskService) catchUpToNow(ctx context.Context) error {
 	const opn = "Tasks.catchUpToNow"
 	log := ts.initLogger(opn)
 
 	log.InfoContext(ctx, "Starting catch-up mode")
 
+	processed := 0
 	for {
 		lastDate, err := ts.GetLastDate(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to get latest processed date: %w", err)
 		}
 
-		today := time.Now().UTC()
-		lastDateUTC := lastDate.UTC()
-
-		todayTruncated := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
-		lastDateTruncated := time.Date(
-			lastDateUTC.Year(),
-			lastDateUTC.Month(),
-			lastDateUTC.Day(),
-			0,
-			0,
-			0,
-			0,
-			time.UTC,
-		)
-
-		if lastDateTruncated.After(todayTruncated) {
+		if utcDay(lastDate).After(ts.catchupBoundary()) {
 			log.InfoContext(
 				ctx,
 				"Catch-up complete. Last processed date is up-to-date.",
 				"lastDate",
 				lastDate.Format("2006-01-02"),
 			)
+			if processed > 0 {
+				ts.analyzeTables(ctx, log)
+			}
 			return nil
 		}
 
@@ -120,66 +309,566 @@ func (ts *TaskService) catchUpToNow(ctx context.Context) error {
 		default:
 		}
 
-		if err = ts.processDate(ctx, lastDate); err != nil {
+		if ts.catchupWorkers > 1 {
+			count, parallelErr := ts.catchUpParallel(ctx, log, lastDate)
+			processed += count
+			if parallelErr != nil {
+				return parallelErr
+			}
+			continue
+		}
+
+		if _, err = ts.processDate(ctx, lastDate); err != nil {
 			return fmt.Errorf("failed to process date %s during catch-up: %w", lastDate.Format("2006-01-02"), err)
 		}
+		processed++
+	}
+}
+
+// dayResult is the outcome of syncing one day of a parallel catch-up.
+type dayResult struct {
+	index   int
+	started time.Time
+	summary DateSummary
+	err     error
+}
+
+// catchUpParallel processes every day from the cursor date up to the catch-up boundary with
+// up to catchupWorkers days in flight, and returns how many days the cursor moved past.
+// Days may finish out of order, so the cursor only advances over the contiguous prefix of
+// synced days, and the rolling hash is not used as it would mix up the hashes of concurrent
+// days. After the first failure no new days are dispatched, the days already in flight are
+// allowed to finish, and the error of the earliest failed day is returned. Days synced past
+// the failed one count as successful runs, but the cursor stays before the failed day, so
+// they are synced again on the next run.
+func (ts *TaskService) catchUpParallel(ctx context.Context, log *slog.Logger, from time.Time) (int, error) {
+	boundary := ts.catchupBoundary()
+	var days []time.Time
+	for day := from; ; day = day.AddDate(0, 0, 1) {
+		if utcDay(day).After(boundary) {
+			break
+		}
+		days = append(days, day)
+	}
+
+	log.InfoContext(ctx, "Processing days in parallel", "days", len(days), "workers", ts.catchupWorkers)
+
+	jobs := make(chan int)
+	results := make(chan dayResult)
+	stop := make(chan struct{})
+
+	go func() {
+		defer close(jobs)
+		for i := range days {
+			select {
+			case jobs <- i:
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range min(ts.catchupWorkers, len(days)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res := dayResult{index: i, started: time.Now()}
+				if res.err = ts.probeDB(ctx, log); res.err == nil {
+					res.summary, res.err = ts.syncDay(ctx, days[i], false)
+				}
+				results <- res
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	synced := make([]*dayResult, len(days))
+	failedAt := len(days)
+	var failure error
+	fail := func(index int, err error) {
+		if failedAt == len(days) {
+			close(stop)
+		}
+		if index < failedAt {
+			failedAt = index
+			failure = fmt.Errorf("failed to process date %s during catch-up: %w", days[index].Format("2006-01-02"), err)
+		}
+	}
+
+	next := 0
+	for res := range results {
+		if res.err != nil {
+			_ = ts.finishDate(ctx, log, res.summary, res.started, res.err)
+			fail(res.index, res.err)
+			continue
+		}
+
+		synced[res.index] = &res
+		for next < failedAt && synced[next] != nil {
+			err := ts.finishDate(ctx, log, synced[next].summary, synced[next].started, ts.advanceCursor(ctx, days[next]))
+			if err != nil {
+				fail(next, err)
+				break
+			}
+			next++
+		}
+	}
+
+	for i := failedAt + 1; i < len(days); i++ {
+		if synced[i] != nil {
+			_ = ts.finishDate(ctx, log, synced[i].summary, synced[i].started, nil)
+		}
+	}
+
+	if failure == nil && ctx.Err() != nil && next < len(days) {
+		log.InfoContext(ctx, "Catch-up cancelled.")
+		return next, fmt.Errorf("context initalize error: %w", ctx.Err())
+	}
+
+	return next, failure
+}
+
+// analyzeTables refreshes the planner statistics of the task tables after catch-up ingested
+// new rows. A failure only makes queries slower until autovacuum runs, so it is just logged.
+func (ts *TaskService) analyzeTables(ctx context.Context, log *slog.Logger) {
+	if err := ts.repo.AnalyzeTables(ctx, "tasks", "task_executors"); err != nil {
+		log.WarnContext(ctx, "Failed to analyze task tables after catch-up", "error", err)
 	}
 }
 
+// utcDay returns the start of the UTC day of t, the basis on which the processed-date cursor
+// is stored and compared.
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// catchupBoundary returns the last day catch-up has to process: today, or yesterday when
+// the current day is left to the maintenance loop.
+func (ts *TaskService) catchupBoundary() time.Time {
+	boundary := utcDay(ts.now())
+	if ts.skipToday {
+		boundary = boundary.AddDate(0, 0, -1)
+	}
+
+	return boundary
+}
+
+// processDate synchronizes a single day and then advances the processed-date cursor past it.
+// The cursor and the known hash only move once every task of the day is stored, so a failure
+// anywhere leaves both unchanged and the next run processes the whole day again.
+// The returned summary counts the tasks of the day, as far as the run got.
 func (ts *TaskService) processDate(ctx context.Context, dateToParse time.Time,
-) error {
+) (DateSummary, error) {
 	const opn = "Tasks.processDate"
 	log := ts.initLogger(opn)
 	startTime := time.Now()
 
+	var summary DateSummary
+	err := ts.probeDB(ctx, log)
+	if err == nil {
+		summary, err = ts.syncDate(ctx, dateToParse)
+	}
+	if err == nil {
+		err = ts.advanceCursor(ctx, dateToParse)
+	}
+
+	return summary, ts.finishDate(ctx, log, summary, startTime, err)
+}
+
+// advanceCursor moves the processed-date cursor past dateToParse.
+func (ts *TaskService) advanceCursor(ctx context.Context, dateToParse time.Time) error {
+	nextDate := dateToParse.AddDate(0, 0, 1)
+	if err := ts.statusRepo.SaveProcessedDate(ctx, nextDate); err != nil {
+		return fmt.Errorf("failed to save next processed date '%s': %w",
+			nextDate.Format("02.01.2006"), dbError{err})
+	}
+
+	return nil
+}
+
+// finishDate records the outcome of processing the date of summary in the breaker, the metrics
+// and the progress, and returns err unchanged.
+func (ts *TaskService) finishDate(ctx context.Context, log *slog.Logger, summary DateSummary, startTime time.Time,
+	err error,
+) error {
+	ts.recordDBResult(err)
+	if err != nil {
+		ts.metrics.Runs.WithLabelValues("failure").Inc()
+		return err
+	}
+
+	log.InfoContext(ctx, "Successfully processed date", "date", summary.Date.Format("02.01.2006"),
+		"saved", summary.Saved, "skipped", summary.Skipped)
+	ts.progressMu.Lock()
+	ts.lastSuccessAt = ts.now()
+	ts.progressMu.Unlock()
+	ts.metrics.Runs.WithLabelValues("success").Inc()
+	ts.metrics.LastSuccessfulRun.WithLabelValues("task").SetToCurrentTime()
+	ts.metrics.RunDuration.WithLabelValues("task").Observe(time.Since(startTime).Seconds())
+	return nil
+}
+
+// probeDB checks database connectivity while the database breaker is open. A successful probe
+// lets the run through as a half-open trial, a failed one skips the run without any writes.
+func (ts *TaskService) probeDB(ctx context.Context, log *slog.Logger) error {
+	if ts.dbHealth == nil {
+		return nil
+	}
+
+	if err := ts.dbHealth.Allow(ctx); err != nil {
+		log.DebugContext(ctx, "Database is still unreachable, skipping run", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// recordDBResult feeds the outcome of a run into the database breaker. Runs that failed
+// for reasons other than the database, e.g. Hermes being down, are not counted.
+func (ts *TaskService) recordDBResult(err error) {
+	if ts.dbHealth == nil {
+		return
+	}
+
+	switch {
+	case err == nil:
+		ts.dbHealth.Success()
+	case errors.As(err, &dbError{}):
+		ts.dbHealth.Failure(err)
+	}
+}
+
+// Backfill re-processes every day in the inclusive range [from, to]. Unlike catch-up,
+// it neither moves the persisted cursor nor uses the rolling hash, so the regular catch-up
+// and maintenance flow is left untouched.
+// Like the cursor, from and to are cut to days in UTC.
+func (ts *TaskService) Backfill(ctx context.Context, from, to time.Time) error {
+	const opn = "Tasks.Backfill"
+	log := ts.initLogger(opn)
+
+	fromDate := utcDay(from)
+	toDate := utcDay(to)
+
+	if fromDate.After(toDate) {
+		return fmt.Errorf("invalid backfill range: from '%s' is after to '%s'",
+			fromDate.Format("2006-01-02"), toDate.Format("2006-01-02"))
+	}
+
+	log.InfoContext(ctx, "Starting backfill", "from", fromDate.Format("2006-01-02"), "to", toDate.Format("2006-01-02"))
+
+	for day := fromDate; !day.After(toDate); day = day.AddDate(0, 0, 1) {
+		select {
+		case <-ctx.Done():
+			log.InfoContext(ctx, "Backfill cancelled.", "date", day.Format("2006-01-02"))
+			return fmt.Errorf("backfill cancelled: %w", ctx.Err())
+		default:
+		}
+
+		if _, err := ts.syncDay(ctx, day, false); err != nil {
+			return fmt.Errorf("failed to backfill date %s: %w", day.Format("2006-01-02"), err)
+		}
+	}
+
+	log.InfoContext(ctx, "Backfill complete", "from", fromDate.Format("2006-01-02"), "to", toDate.Format("2006-01-02"))
+	return nil
+}
+
+// syncDate fetches the tasks for a single day from Hermes and persists them.
+// It does not touch the processed-date cursor.
+func (ts *TaskService) syncDate(ctx context.Context, dateToParse time.Time) (DateSummary, error) {
+	return ts.syncDay(ctx, dateToParse, true)
+}
+
+// syncDay is syncDate for days that may be synced concurrently with other days: unless rolling is
+// set, the rolling hash is neither sent nor updated, as it only describes the previous request of
+// a sequential run. Per-date hashes are used either way.
+func (ts *TaskService) syncDay(ctx context.Context, dateToParse time.Time, rolling bool) (DateSummary, error) {
+	const opn = "Tasks.syncDate"
+	log := ts.initLogger(opn)
+
 	normalizedDate := time.Date(
 		dateToParse.Year(), dateToParse.Month(), dateToParse.Day(), 0, 0, 0, 0, time.UTC)
 
 	dateKey := normalizedDate.Format("2006-01-02")
 	log.DebugContext(ctx, "Scraping data", "date", dateKey)
 
+	summary := DateSummary{Date: normalizedDate}
+	knownHash, err := ts.knownHash(ctx, normalizedDate, rolling)
+	if err != nil {
+		return summary, err
+	}
+
 	req := &pb.GetDailyTasksRequest{
-		KnownHash: ts.lastKnownHash,
+		KnownHash: knownHash,
 		Date:      wrapperspb.String(dateKey),
 	}
 	resp, err := ts.hermesClient.GetDailyTasks(ctx, req)
 	if err != nil {
-		ts.metrics.Runs.WithLabelValues("failure").Inc()
-		return fmt.Errorf("failed to get tasks for date '%s' from Hermes: %w", dateKey, err)
+		return summary, fmt.Errorf("failed to get tasks for date '%s' from Hermes: %w", dateKey, err)
 	}
 
-	if len(resp.GetTasks()) == 0 || ts.lastKnownHash == resp.GetNewHash() {
-		log.DebugContext(ctx, "No new tasks found for date", "date", dateKey)
-	} else {
+	switch {
+	case knownHash == resp.GetNewHash():
+		log.DebugContext(ctx, "Tasks are unchanged. Hashes match.", "date", dateKey, "hash", resp.GetNewHash())
+		ts.metrics.SyncResults.WithLabelValues("task", "unchanged").Inc()
+		summary.Skipped = len(resp.GetTasks())
+		ts.metrics.TasksProcessed.WithLabelValues("skipped").Add(float64(summary.Skipped))
+		ts.observeDataChange(false)
+	case len(resp.GetTasks()) == 0:
+		log.DebugContext(ctx, "Hermes has no tasks for date", "date", dateKey, "hash", resp.GetNewHash())
+		ts.metrics.SyncResults.WithLabelValues("task", "no_data").Inc()
+	default:
 		log.InfoContext(ctx, "New data received from Hermes", "date", dateKey, "count", len(resp.GetTasks()))
-		tasks := convertPbTasksToModels(resp.GetTasks())
+		ts.metrics.SyncResults.WithLabelValues("task", "new_data").Inc()
+		ts.observeDataChange(true)
+		tasks := ts.filterTasks(convertPbTasksToModels(resp.GetTasks()))
+		summary.Skipped = len(resp.GetTasks()) - len(tasks)
+		ts.metrics.TasksProcessed.WithLabelValues("skipped").Add(float64(summary.Skipped))
+		var closedBefore map[int]bool
+		if closedBefore, err = ts.storedClosedStates(ctx, tasks); err != nil {
+			return summary, err
+		}
 		for _, task := range tasks {
-			if err = ts.repo.SaveTaskData(ctx, task); err != nil {
-				ts.metrics.Runs.WithLabelValues("failure").Inc()
-				return fmt.Errorf("failed to save task '%d': %w", task.ID, err)
+			var saved bool
+			if saved, err = ts.saveTask(ctx, log, task); err != nil {
+				return summary, err
+			}
+			if !saved {
+				continue
+			}
+			summary.Saved++
+			ts.metrics.TasksProcessed.WithLabelValues("saved").Inc()
+			if wasClosed, stored := closedBefore[task.ID]; stored && !wasClosed && task.IsClosed {
+				ts.observer.OnTaskClosed(ctx, task)
 			}
 		}
 	}
 
-	ts.lastKnownHash = resp.GetNewHash()
-	nextDate := dateToParse.AddDate(0, 0, 1)
-	if err = ts.statusRepo.SaveProcessedDate(ctx, nextDate); err != nil {
-		ts.metrics.Runs.WithLabelValues("failure").Inc()
-		return fmt.Errorf("failed to save next processed date '%s': %w", nextDate.Format("02.01.2006"), err)
+	if err = ts.rememberHash(ctx, normalizedDate, knownHash, resp.GetNewHash(), rolling); err != nil {
+		return summary, err
+	}
+
+	ts.progressMu.Lock()
+	ts.totals.Saved += summary.Saved
+	ts.totals.Skipped += summary.Skipped
+	ts.progressMu.Unlock()
+
+	return summary, nil
+}
+
+// saveTask stores task. With the dead-letter queue enabled, a failing save is attempted up to
+// deadLetterAfter times within the run and the task is then moved to the queue instead of failing
+// the day, so that a task that keeps failing cannot stall catch-up across restarts. It reports
+// whether the task was stored.
+func (ts *TaskService) saveTask(ctx context.Context, log *slog.Logger, task models.Task) (bool, error) {
+	attempts := 0
+	var err error
+	for attempts < max(ts.deadLetterAfter, 1) {
+		attempts++
+		if err = ts.repo.SaveTaskData(ctx, task); err == nil {
+			return true, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	// The task counts once, as failed or as dead-lettered. A cancelled run is not the fault of
+	// the task, so it is left for the next run.
+	if ts.deadLetter == nil || ctx.Err() != nil {
+		ts.metrics.TasksProcessed.WithLabelValues("failed").Inc()
+		return false, fmt.Errorf("failed to save task '%d': %w", task.ID, dbError{err})
+	}
+
+	if dlErr := ts.deadLetter.SaveFailedTask(ctx, task, attempts, err.Error()); dlErr != nil {
+		ts.metrics.TasksProcessed.WithLabelValues("failed").Inc()
+		return false, fmt.Errorf("failed to dead-letter task '%d': %w", task.ID, dbError{dlErr})
+	}
+
+	ts.metrics.TasksProcessed.WithLabelValues("dead_lettered").Inc()
+	log.WarnContext(ctx, "Task moved to the dead-letter queue", "task_id", task.ID, "attempts", attempts,
+		"error", err)
+
+	return false, nil
+}
+
+// FailedTasks returns the tasks in the dead-letter queue.
+func (ts *TaskService) FailedTasks(ctx context.Context) ([]models.FailedTask, error) {
+	if ts.deadLetter == nil {
+		return nil, nil
+	}
+
+	failed, err := ts.deadLetter.ListFailedTasks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead-lettered tasks: %w", err)
+	}
+
+	return failed, nil
+}
+
+// RetryFailedTasks tries to save every dead-lettered task again and removes the ones that succeed
+// from the queue. It returns how many tasks were saved; tasks that still fail stay in the queue.
+func (ts *TaskService) RetryFailedTasks(ctx context.Context) (int, error) {
+	const opn = "Tasks.RetryFailedTasks"
+	log := ts.initLogger(opn)
+
+	failed, err := ts.FailedTasks(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	retried := 0
+	for _, item := range failed {
+		if err = ts.repo.SaveTaskData(ctx, item.Task); err != nil {
+			log.WarnContext(ctx, "Dead-lettered task still fails to save", "task_id", item.Task.ID, "error", err)
+			continue
+		}
+		if err = ts.deadLetter.DeleteFailedTask(ctx, item.Task.ID); err != nil {
+			return retried, fmt.Errorf("failed to remove retried task '%d' from the dead-letter queue: %w",
+				item.Task.ID, err)
+		}
+		retried++
+	}
+
+	log.InfoContext(ctx, "Retried dead-lettered tasks", "saved", retried, "remaining", len(failed)-retried)
+	return retried, nil
+}
+
+// storedClosedStates returns the stored closed state of tasks when an observer has to be told
+// about closed tasks, and nil otherwise.
+func (ts *TaskService) storedClosedStates(ctx context.Context, tasks []models.Task) (map[int]bool, error) {
+	if ts.observer == nil || len(tasks) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+
+	states, err := ts.repo.GetTaskClosedStates(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored task states: %w", dbError{err})
+	}
+
+	return states, nil
+}
+
+// observeDataChange exposes how long Hermes has been returning the same data. The time is counted
+// from the last changed response, or from the first observation after the service started.
+func (ts *TaskService) observeDataChange(changed bool) {
+	ts.syncMu.Lock()
+	defer ts.syncMu.Unlock()
+
+	now := ts.now()
+	if changed || ts.dataChangedAt.IsZero() {
+		ts.dataChangedAt = now
+	}
+	ts.metrics.DataUnchangedSeconds.WithLabelValues("task").Set(now.Sub(ts.dataChangedAt).Seconds())
+}
+
+// filterTasks drops the tasks the service is configured not to ingest.
+func (ts *TaskService) filterTasks(tasks []models.Task) []models.Task {
+	if !ts.onlyClosed {
+		return tasks
+	}
+
+	closed := make([]models.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if !isCompleted(task) {
+			ts.metrics.FilteredItems.WithLabelValues("task", "open").Inc()
+			continue
+		}
+		closed = append(closed, task)
+	}
+
+	return closed
+}
+
+// isCompleted reports whether the task has a closing date. An unset closing_date is converted
+// to the Unix epoch rather than to the zero time, so both are treated as absent.
+func isCompleted(task models.Task) bool {
+	return !task.ClosedAt.IsZero() && !task.ClosedAt.Equal(time.Unix(0, 0))
+}
+
+// knownHash returns the hash of the last tasks received for date: the stored per-date hash
+// when per-date hashes are enabled, the rolling hash of the previous request if rolling is set,
+// and an empty hash otherwise.
+func (ts *TaskService) knownHash(ctx context.Context, date time.Time, rolling bool) (string, error) {
+	if ts.dateHashes == nil {
+		if !rolling {
+			return "", nil
+		}
+		ts.progressMu.Lock()
+		defer ts.progressMu.Unlock()
+		return ts.lastKnownHash, nil
+	}
+
+	hash, err := ts.dateHashes.GetDateHash(ctx, date)
+	if err != nil {
+		return "", fmt.Errorf("failed to get known hash: %w", dbError{err})
+	}
+
+	return hash, nil
+}
+
+// rememberHash records newHash as the known hash for date. The rolling hash is only updated
+// if rolling is set.
+func (ts *TaskService) rememberHash(ctx context.Context, date time.Time, knownHash, newHash string,
+	rolling bool,
+) error {
+	if ts.dateHashes == nil {
+		if rolling {
+			ts.progressMu.Lock()
+			ts.lastKnownHash = newHash
+			ts.progressMu.Unlock()
+		}
+		return nil
+	}
+
+	if knownHash == newHash {
+		return nil
+	}
+
+	if err := ts.dateHashes.SaveDateHash(ctx, date, newHash); err != nil {
+		return fmt.Errorf("failed to save known hash: %w", dbError{err})
 	}
 
-	log.InfoContext(ctx, "Successfully processed date", "date", dateToParse.Format("02.01.2006"))
-	ts.metrics.Runs.WithLabelValues("success").Inc()
-	ts.metrics.LastSuccessfulRun.WithLabelValues("task").SetToCurrentTime()
-	ts.metrics.RunDuration.WithLabelValues("task").Observe(time.Since(startTime).Seconds())
 	return nil
 }
 
+// Progress returns the last hash received from Hermes and the time of the last successfully
+// processed date, which is zero until a date succeeds. The hash is empty when per-date hashes are
+// kept in the database. It is safe to call while the service is running.
+func (ts *TaskService) Progress() (string, time.Time) {
+	ts.progressMu.Lock()
+	defer ts.progressMu.Unlock()
+
+	return ts.lastKnownHash, ts.lastSuccessAt
+}
+
+// Counts returns how many tasks were saved and skipped over all the dates synced since the start.
+func (ts *TaskService) Counts() map[string]int {
+	ts.progressMu.Lock()
+	defer ts.progressMu.Unlock()
+
+	return map[string]int{"saved": ts.totals.Saved, "skipped": ts.totals.Skipped}
+}
+
 func (ts *TaskService) GetLastDate(ctx context.Context) (time.Time, error) {
 	lastDate, err := ts.statusRepo.GetLastProcessedDate(ctx)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			lastDate = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			lastDate = ts.catchupStartDate
 		} else {
 			return time.Time{}, fmt.Errorf("failed to get latest processed date: %w", err)
 		}
@@ -207,20 +896,35 @@ func (ts *TaskService) updateTaskTypes(ctx context.Context) error {
 func convertPbTasksToModels(pbTasks []*pb.Task) []models.Task {
 	tasks := make([]models.Task, 0, len(pbTasks))
 	for _, pbt := range pbTasks {
+		// Strings are normalized to NFC so that differently encoded but equal text
+		// does not change the stored content hash.
 		task := models.Task{
 			ID:            int(pbt.GetId()),
-			Type:          pbt.GetType(),
+			Type:          norm.NFC.String(pbt.GetType()),
 			CreatedAt:     pbt.GetCreationDate().AsTime(),
 			ClosedAt:      pbt.GetClosingDate().AsTime(),
-			Description:   pbt.GetDescription(),
-			Address:       pbt.GetAddress(),
-			CustomerName:  pbt.GetCustomerName(),
-			CustomerLogin: pbt.GetCustomerLogin(),
-			Comments:      pbt.GetComments(),
-			Executors:     pbt.GetExecutors(),
+			Description:   norm.NFC.String(pbt.GetDescription()),
+			Address:       norm.NFC.String(pbt.GetAddress()),
+			CustomerName:  norm.NFC.String(pbt.GetCustomerName()),
+			CustomerLogin: norm.NFC.String(pbt.GetCustomerLogin()),
+			Comments:      normalizeStrings(pbt.GetComments()),
+			Executors:     normalizeStrings(pbt.GetExecutors()),
 			IsClosed:      pbt.GetIsClosed(),
 		}
 		tasks = append(tasks, task)
 	}
 	return tasks
 }
+
+// normalizeStrings returns a copy of values with every element normalized to NFC.
+func normalizeStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+
+	normalized := make([]string, len(values))
+	for i, value := range values {
+		normalized[i] = norm.NFC.String(value)
+	}
+	return normalized
+}
@@ -0,0 +1,1189 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/UnknownOlympus/hephaestus/internal/lib/breaker"
+	"github.com/UnknownOlympus/hephaestus/internal/lib/outage"
+	"github.com/UnknownOlympus/hephaestus/internal/metrics"
+	"github.com/UnknownOlympus/hephaestus/internal/models"
+	"github.com/UnknownOlympus/hephaestus/internal/repository/memory"
+	"github.com/UnknownOlympus/hephaestus/internal/services/employees"
+	mocks "github.com/UnknownOlympus/hephaestus/mock"
+	pb "github.com/UnknownOlympus/olympus-protos/gen/go/scraper/olympus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newTestTaskService(t *testing.T) (*TaskService, *mocks.TaskRepoIface, *mocks.StatusRepoIface,
+	*mocks.ScraperServiceClient,
+) {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mockRepo := mocks.NewTaskRepoIface(t)
+	mockStatus := mocks.NewStatusRepoIface(t)
+	mockHermes := mocks.NewScraperServiceClient(t)
+	testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+
+	return NewTaskService(logger, mockRepo, mockStatus, testMetrics, mockHermes), mockRepo, mockStatus, mockHermes
+}
+
+func dailyTasksForDate(date string) any {
+	return mock.MatchedBy(func(req *pb.GetDailyTasksRequest) bool {
+		return req.GetDate().GetValue() == date
+	})
+}
+
+func TestBackfill(t *testing.T) {
+	t.Run("should return error for inverted range", func(t *testing.T) {
+		service, _, mockStatus, mockHermes := newTestTaskService(t)
+
+		from := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+		err := service.Backfill(t.Context(), from, to)
+
+		require.Error(t, err)
+		require.ErrorContains(t, err, "invalid backfill range")
+		mockHermes.AssertNotCalled(t, "GetDailyTasks")
+		mockStatus.AssertNotCalled(t, "SaveProcessedDate")
+	})
+
+	t.Run("should process every day in range without moving the cursor", func(t *testing.T) {
+		service, mockRepo, mockStatus, mockHermes := newTestTaskService(t)
+		service.lastKnownHash = "hash_today"
+
+		from := time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC)
+		to := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+
+		var calls []string
+		for _, date := range []string{"2024-03-01", "2024-03-02", "2024-03-03"} {
+			mockHermes.On("GetDailyTasks", mock.Anything, mock.MatchedBy(func(req *pb.GetDailyTasksRequest) bool {
+				return req.GetDate().GetValue() == date && req.GetKnownHash() == ""
+			})).
+				Run(func(_ mock.Arguments) { calls = append(calls, date) }).
+				Return(&pb.GetDailyTasksResponse{NewHash: "hash_" + date}, nil).
+				Once()
+		}
+
+		err := service.Backfill(t.Context(), from, to)
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"2024-03-01", "2024-03-02", "2024-03-03"}, calls)
+		require.Equal(t, "hash_today", service.lastKnownHash)
+		mockHermes.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "SaveTaskData")
+		mockStatus.AssertNotCalled(t, "SaveProcessedDate")
+	})
+
+	t.Run("should cut the range to days in UTC", func(t *testing.T) {
+		service, _, mockStatus, mockHermes := newTestTaskService(t)
+		utcPlus3 := time.FixedZone("UTC+3", 3*60*60)
+
+		// 2024-03-02 01:30 and 2024-03-03 02:00 in UTC+3 are still 2024-03-01 and 2024-03-02 in UTC.
+		from := time.Date(2024, 3, 2, 1, 30, 0, 0, utcPlus3)
+		to := time.Date(2024, 3, 3, 2, 0, 0, 0, utcPlus3)
+
+		var calls []string
+		for _, date := range []string{"2024-03-01", "2024-03-02"} {
+			mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate(date)).
+				Run(func(_ mock.Arguments) { calls = append(calls, date) }).
+				Return(&pb.GetDailyTasksResponse{NewHash: "hash_" + date}, nil).
+				Once()
+		}
+
+		err := service.Backfill(t.Context(), from, to)
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"2024-03-01", "2024-03-02"}, calls)
+		mockStatus.AssertNotCalled(t, "SaveProcessedDate")
+	})
+
+	t.Run("should process a single day when from equals to", func(t *testing.T) {
+		service, _, mockStatus, mockHermes := newTestTaskService(t)
+
+		day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash"}, nil).
+			Once()
+
+		err := service.Backfill(t.Context(), day, day)
+
+		require.NoError(t, err)
+		mockHermes.AssertExpectations(t)
+		mockStatus.AssertNotCalled(t, "SaveProcessedDate")
+	})
+
+	t.Run("should stop when context is cancelled", func(t *testing.T) {
+		service, _, _, mockHermes := newTestTaskService(t)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		err := service.Backfill(ctx, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC))
+
+		require.ErrorIs(t, err, context.Canceled)
+		mockHermes.AssertNotCalled(t, "GetDailyTasks")
+	})
+}
+
+func TestGetLastDate(t *testing.T) {
+	t.Run("should fall back to the configured catch-up start date", func(t *testing.T) {
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		mockStatus := mocks.NewStatusRepoIface(t)
+		startDate := time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)
+		service := NewTaskService(logger, mocks.NewTaskRepoIface(t), mockStatus,
+			metrics.NewMetrics(prometheus.NewRegistry()), mocks.NewScraperServiceClient(t),
+			WithCatchupStartDate(startDate))
+
+		mockStatus.On("GetLastProcessedDate", mock.Anything).Return(time.Time{}, sql.ErrNoRows).Once()
+
+		lastDate, err := service.GetLastDate(t.Context())
+
+		require.NoError(t, err)
+		require.Equal(t, startDate, lastDate)
+	})
+
+	t.Run("should default to 2024-01-01", func(t *testing.T) {
+		service, _, mockStatus, _ := newTestTaskService(t)
+
+		mockStatus.On("GetLastProcessedDate", mock.Anything).Return(time.Time{}, sql.ErrNoRows).Once()
+
+		lastDate, err := service.GetLastDate(t.Context())
+
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), lastDate)
+	})
+}
+
+func TestReportRunError(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+	service := NewTaskService(logger, mocks.NewTaskRepoIface(t), mocks.NewStatusRepoIface(t), testMetrics,
+		mocks.NewScraperServiceClient(t), WithRepeatedErrorLogEvery(2))
+	gauge := testMetrics.RepeatedErrors.WithLabelValues("task")
+
+	t.Run("should count identical errors", func(t *testing.T) {
+		for range 3 {
+			service.reportRunError(t.Context(), logger, errors.New("schema mismatch"))
+		}
+
+		require.InDelta(t, 3, testutil.ToFloat64(gauge), 0)
+	})
+
+	t.Run("should restart the count when the error changes", func(t *testing.T) {
+		service.reportRunError(t.Context(), logger, errors.New("connection refused"))
+
+		require.InDelta(t, 1, testutil.ToFloat64(gauge), 0)
+	})
+
+	t.Run("should reset after a successful run", func(t *testing.T) {
+		service.resetRunError()
+
+		require.InDelta(t, 0, testutil.ToFloat64(gauge), 0)
+	})
+}
+
+func TestSyncDate_EmptyResponses(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+	mockHermes := mocks.NewScraperServiceClient(t)
+	mockRepo := mocks.NewTaskRepoIface(t)
+	service := NewTaskService(logger, mockRepo, mocks.NewStatusRepoIface(t), testMetrics, mockHermes)
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("empty list with a new hash counts as no data", func(t *testing.T) {
+		mockHermes.On("GetDailyTasks", mock.Anything, mock.Anything).
+			Return(&pb.GetDailyTasksResponse{NewHash: "empty_hash"}, nil).
+			Once()
+
+		_, err := service.syncDate(t.Context(), day)
+
+		require.NoError(t, err)
+		require.Equal(t, "empty_hash", service.lastKnownHash)
+		require.InDelta(t, 1, testutil.ToFloat64(testMetrics.SyncResults.WithLabelValues("task", "no_data")), 0)
+		require.InDelta(t, 0, testutil.ToFloat64(testMetrics.SyncResults.WithLabelValues("task", "unchanged")), 0)
+	})
+
+	t.Run("matching hash counts as unchanged", func(t *testing.T) {
+		mockHermes.On("GetDailyTasks", mock.Anything, mock.Anything).
+			Return(&pb.GetDailyTasksResponse{NewHash: "empty_hash"}, nil).
+			Once()
+
+		_, err := service.syncDate(t.Context(), day)
+
+		require.NoError(t, err)
+		require.InDelta(t, 1, testutil.ToFloat64(testMetrics.SyncResults.WithLabelValues("task", "no_data")), 0)
+		require.InDelta(t, 1, testutil.ToFloat64(testMetrics.SyncResults.WithLabelValues("task", "unchanged")), 0)
+		mockRepo.AssertNotCalled(t, "SaveTaskData")
+	})
+}
+
+func TestCatchUpToNow_TodayBoundary(t *testing.T) {
+	// A few seconds before midnight UTC, so "today" is still 2024-03-10.
+	now := time.Date(2024, 3, 10, 23, 59, 30, 0, time.UTC)
+	lastProcessed := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		skipToday bool
+		processed []string
+	}{
+		{name: "processes up to and including today", skipToday: false, processed: []string{"2024-03-09", "2024-03-10"}},
+		{name: "stops at yesterday", skipToday: true, processed: []string{"2024-03-09"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+			mockRepo := mocks.NewTaskRepoIface(t)
+			mockStatus := mocks.NewStatusRepoIface(t)
+			mockHermes := mocks.NewScraperServiceClient(t)
+			service := NewTaskService(logger, mockRepo, mockStatus,
+				metrics.NewMetrics(prometheus.NewRegistry()), mockHermes, WithCatchupSkipToday(tt.skipToday))
+			service.now = func() time.Time { return now }
+
+			cursor := lastProcessed
+			for _, date := range tt.processed {
+				mockStatus.On("GetLastProcessedDate", mock.Anything).Return(cursor, nil).Once()
+				mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate(date)).
+					Return(&pb.GetDailyTasksResponse{NewHash: "hash_" + date}, nil).
+					Once()
+				cursor = cursor.AddDate(0, 0, 1)
+				mockStatus.On("SaveProcessedDate", mock.Anything, cursor).Return(nil).Once()
+			}
+			mockStatus.On("GetLastProcessedDate", mock.Anything).Return(cursor, nil).Once()
+			mockRepo.On("AnalyzeTables", mock.Anything, "tasks", "task_executors").Return(nil).Once()
+
+			err := service.catchUpToNow(t.Context())
+
+			require.NoError(t, err)
+			mockHermes.AssertExpectations(t)
+			mockStatus.AssertExpectations(t)
+		})
+	}
+}
+
+type fakePinger struct {
+	err   error
+	calls int
+}
+
+func (p *fakePinger) Ping(_ context.Context) error {
+	p.calls++
+	return p.err
+}
+
+func TestProcessDate_DBBreaker(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+	mockRepo := mocks.NewTaskRepoIface(t)
+	mockStatus := mocks.NewStatusRepoIface(t)
+	mockHermes := mocks.NewScraperServiceClient(t)
+	pinger := &fakePinger{}
+	service := NewTaskService(logger, mockRepo, mockStatus, testMetrics, mockHermes, WithDBBreaker(2, pinger))
+	gauge := testMetrics.CircuitState.WithLabelValues("db", "task")
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	dbErr := errors.New("connection refused")
+
+	// Every run gets a new hash so that tasks are always written.
+	expectTasks := func(hash string) {
+		mockHermes.On("GetDailyTasks", mock.Anything, mock.Anything).
+			Return(&pb.GetDailyTasksResponse{NewHash: hash, Tasks: []*pb.Task{{Id: 1, Type: "Repair"}}}, nil).
+			Once()
+	}
+
+	t.Run("opens after repeated save errors", func(t *testing.T) {
+		require.InDelta(t, float64(breaker.Closed), testutil.ToFloat64(gauge), 0)
+
+		expectTasks("hash_1")
+		expectTasks("hash_2")
+		mockRepo.On("SaveTaskData", mock.Anything, mock.AnythingOfType("models.Task")).Return(dbErr).Twice()
+
+		_, err := service.processDate(t.Context(), day)
+		require.ErrorIs(t, err, dbErr)
+		require.Equal(t, breaker.Closed, service.dbHealth.State())
+		_, err = service.processDate(t.Context(), day)
+		require.ErrorIs(t, err, dbErr)
+
+		require.Equal(t, breaker.Open, service.dbHealth.State())
+		require.InDelta(t, float64(breaker.Open), testutil.ToFloat64(gauge), 0)
+	})
+
+	t.Run("skips writes while the database is unreachable", func(t *testing.T) {
+		pinger.err = dbErr
+
+		_, err := service.processDate(t.Context(), day)
+
+		require.ErrorIs(t, err, ErrDBCircuitOpen)
+		require.Equal(t, 1, pinger.calls)
+		mockHermes.AssertNumberOfCalls(t, "GetDailyTasks", 2)
+		mockRepo.AssertNumberOfCalls(t, "SaveTaskData", 2)
+	})
+
+	t.Run("recovers after a successful probe", func(t *testing.T) {
+		pinger.err = nil
+		expectTasks("hash_3")
+		mockRepo.On("SaveTaskData", mock.Anything, mock.AnythingOfType("models.Task")).Return(nil).Once()
+		mockStatus.On("SaveProcessedDate", mock.Anything, day.AddDate(0, 0, 1)).Return(nil).Once()
+
+		_, err := service.processDate(t.Context(), day)
+
+		require.NoError(t, err)
+		require.Equal(t, 2, pinger.calls)
+		require.Equal(t, breaker.Closed, service.dbHealth.State())
+		require.InDelta(t, float64(breaker.Closed), testutil.ToFloat64(gauge), 0)
+	})
+
+	t.Run("Hermes errors do not count towards the breaker", func(t *testing.T) {
+		failingHermes := mocks.NewScraperServiceClient(t)
+		hermesOnly := NewTaskService(logger, mocks.NewTaskRepoIface(t), mocks.NewStatusRepoIface(t),
+			metrics.NewMetrics(prometheus.NewRegistry()), failingHermes, WithDBBreaker(1, pinger))
+		failingHermes.On("GetDailyTasks", mock.Anything, mock.Anything).Return(nil, errors.New("unavailable")).Once()
+
+		_, err := hermesOnly.processDate(t.Context(), day)
+		require.Error(t, err)
+		require.Equal(t, breaker.Closed, hermesOnly.dbHealth.State())
+	})
+}
+
+func TestDBOutage_PausesAllServices(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+	pinger := &fakePinger{}
+	coordinator := outage.New(logger, "database", 2, pinger, testMetrics.CircuitState.WithLabelValues("db", "shared"))
+	dbErr := errors.New("connection refused")
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	taskRepo := mocks.NewTaskRepoIface(t)
+	taskStatus := mocks.NewStatusRepoIface(t)
+	taskHermes := mocks.NewScraperServiceClient(t)
+	taskService := NewTaskService(logger, taskRepo, taskStatus, testMetrics, taskHermes,
+		WithDBCoordinator(coordinator))
+
+	employeeRepo := mocks.NewEmployeeRepoIface(t)
+	employeeHermes := mocks.NewScraperServiceClient(t)
+	staff := employees.NewStaff(logger, employeeRepo, testMetrics, employeeHermes,
+		employees.WithDBCoordinator(coordinator))
+
+	expectTasks := func(hash string) {
+		taskHermes.On("GetDailyTasks", mock.Anything, mock.Anything).
+			Return(&pb.GetDailyTasksResponse{NewHash: hash, Tasks: []*pb.Task{{Id: 1, Type: "Repair"}}}, nil).
+			Once()
+	}
+	expectEmployees := func(hash string) {
+		employeeHermes.On("GetEmployees", mock.Anything, mock.Anything).
+			Return(&pb.GetEmployeesResponse{NewHash: hash, Employees: []*pb.Employee{
+				{Id: 1, Fullname: "New Employee", Email: "new@example.com"},
+			}}, nil).
+			Once()
+		employeeRepo.On("GetEmployeeByID", mock.Anything, 1).Return(models.Employee{}, sql.ErrNoRows).Once()
+	}
+
+	t.Run("failures in both services confirm the outage", func(t *testing.T) {
+		expectEmployees("emp_1")
+		employeeRepo.On("SaveEmployee", mock.Anything, 1, "New Employee", "", "", "new@example.com", "").
+			Return(dbErr).Once()
+		expectTasks("task_1")
+		taskRepo.On("SaveTaskData", mock.Anything, mock.AnythingOfType("models.Task")).Return(dbErr).Once()
+
+		_, err := staff.ProcessEmployee(t.Context())
+		require.ErrorIs(t, err, dbErr)
+		require.False(t, coordinator.Paused())
+		_, err = taskService.processDate(t.Context(), day)
+		require.ErrorIs(t, err, dbErr)
+
+		require.True(t, coordinator.Paused())
+	})
+
+	t.Run("both services are paused during the outage", func(t *testing.T) {
+		pinger.err = dbErr
+
+		_, err := staff.ProcessEmployee(t.Context())
+		require.ErrorIs(t, err, outage.ErrPaused)
+		_, err = taskService.processDate(t.Context(), day)
+		require.ErrorIs(t, err, ErrDBCircuitOpen)
+
+		require.Equal(t, 2, pinger.calls)
+		employeeHermes.AssertNumberOfCalls(t, "GetEmployees", 1)
+		taskHermes.AssertNumberOfCalls(t, "GetDailyTasks", 1)
+	})
+
+	t.Run("both services resume after recovery", func(t *testing.T) {
+		pinger.err = nil
+		expectTasks("task_2")
+		taskRepo.On("SaveTaskData", mock.Anything, mock.AnythingOfType("models.Task")).Return(nil).Once()
+		taskStatus.On("SaveProcessedDate", mock.Anything, day.AddDate(0, 0, 1)).Return(nil).Once()
+
+		_, err := taskService.processDate(t.Context(), day)
+		require.NoError(t, err)
+		require.False(t, coordinator.Paused())
+
+		expectEmployees("emp_2")
+		employeeRepo.On("SaveEmployee", mock.Anything, 1, "New Employee", "", "", "new@example.com", "").
+			Return(nil).Once()
+
+		_, err = staff.ProcessEmployee(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, 3, pinger.calls, "a closed breaker must not be probed")
+	})
+}
+
+func TestSyncDate_DateHashes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+	mockRepo := mocks.NewTaskRepoIface(t)
+	mockHermes := mocks.NewScraperServiceClient(t)
+	mockHashes := mocks.NewDateHashRepoIface(t)
+	service := NewTaskService(logger, mockRepo, mocks.NewStatusRepoIface(t), testMetrics, mockHermes,
+		WithDateHashes(mockHashes))
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	otherDay := day.AddDate(0, 0, 1)
+
+	t.Run("skips a date whose stored hash matches", func(t *testing.T) {
+		mockHashes.On("GetDateHash", mock.Anything, day).Return("hash_1", nil).Once()
+		mockHermes.On("GetDailyTasks", mock.Anything, mock.MatchedBy(func(req *pb.GetDailyTasksRequest) bool {
+			return req.GetKnownHash() == "hash_1"
+		})).Return(&pb.GetDailyTasksResponse{NewHash: "hash_1"}, nil).Once()
+
+		_, err := service.syncDate(t.Context(), day)
+
+		require.NoError(t, err)
+		require.InDelta(t, 1, testutil.ToFloat64(testMetrics.SyncResults.WithLabelValues("task", "unchanged")), 0)
+		mockRepo.AssertNotCalled(t, "SaveTaskData")
+		mockHashes.AssertNotCalled(t, "SaveDateHash")
+	})
+
+	t.Run("processes and stores a changed date", func(t *testing.T) {
+		mockHashes.On("GetDateHash", mock.Anything, day).Return("hash_1", nil).Once()
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_2", Tasks: []*pb.Task{{Id: 1, Type: "Repair"}}}, nil).
+			Once()
+		mockRepo.On("SaveTaskData", mock.Anything, mock.AnythingOfType("models.Task")).Return(nil).Once()
+		mockHashes.On("SaveDateHash", mock.Anything, day, "hash_2").Return(nil).Once()
+
+		_, err := service.syncDate(t.Context(), day)
+
+		require.NoError(t, err)
+		mockHashes.AssertExpectations(t)
+	})
+
+	t.Run("does not depend on the hash of another date", func(t *testing.T) {
+		mockHashes.On("GetDateHash", mock.Anything, otherDay).Return("", nil).Once()
+		mockHermes.On("GetDailyTasks", mock.Anything, mock.MatchedBy(func(req *pb.GetDailyTasksRequest) bool {
+			return req.GetKnownHash() == ""
+		})).Return(&pb.GetDailyTasksResponse{NewHash: "hash_2", Tasks: []*pb.Task{{Id: 2, Type: "Repair"}}}, nil).
+			Once()
+		mockRepo.On("SaveTaskData", mock.Anything, mock.AnythingOfType("models.Task")).Return(nil).Once()
+		mockHashes.On("SaveDateHash", mock.Anything, otherDay, "hash_2").Return(nil).Once()
+
+		_, err := service.syncDate(t.Context(), otherDay)
+
+		require.NoError(t, err)
+		mockRepo.AssertNumberOfCalls(t, "SaveTaskData", 2)
+	})
+
+	t.Run("returns error when the stored hash cannot be read", func(t *testing.T) {
+		mockHashes.On("GetDateHash", mock.Anything, day).Return("", errors.New("connection refused")).Once()
+
+		_, err := service.syncDate(t.Context(), day)
+
+		require.ErrorContains(t, err, "failed to get known hash")
+		require.ErrorAs(t, err, &dbError{})
+	})
+}
+
+func TestCatchUpToNow_AnalyzeTables(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	today := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("analyzes task tables after processing dates", func(t *testing.T) {
+		service, mockRepo, mockStatus, mockHermes := newTestTaskService(t)
+		service.now = func() time.Time { return now }
+
+		mockStatus.On("GetLastProcessedDate", mock.Anything).Return(today, nil).Once()
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-10")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash"}, nil).
+			Once()
+		mockStatus.On("SaveProcessedDate", mock.Anything, today.AddDate(0, 0, 1)).Return(nil).Once()
+		mockStatus.On("GetLastProcessedDate", mock.Anything).Return(today.AddDate(0, 0, 1), nil).Once()
+		mockRepo.On("AnalyzeTables", mock.Anything, "tasks", "task_executors").
+			Return(errors.New("permission denied")).
+			Once()
+
+		err := service.catchUpToNow(t.Context())
+
+		require.NoError(t, err, "a failed ANALYZE must not fail catch-up")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("skips analyze when nothing was processed", func(t *testing.T) {
+		service, mockRepo, mockStatus, _ := newTestTaskService(t)
+		service.now = func() time.Time { return now }
+
+		mockStatus.On("GetLastProcessedDate", mock.Anything).Return(today.AddDate(0, 0, 1), nil).Once()
+
+		err := service.catchUpToNow(t.Context())
+
+		require.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "AnalyzeTables")
+	})
+}
+
+func TestStart_SafeMode(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mockRepo := mocks.NewTaskRepoIface(t)
+	mockStatus := mocks.NewStatusRepoIface(t)
+	mockHermes := mocks.NewScraperServiceClient(t)
+	service := NewTaskService(logger, mockRepo, mockStatus, metrics.NewMetrics(prometheus.NewRegistry()),
+		mockHermes, WithSafeMode("database schema mismatch"))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err := service.Start(ctx, time.Millisecond)
+
+	require.NoError(t, err)
+	mockHermes.AssertNotCalled(t, "GetTaskTypes")
+	mockRepo.AssertNotCalled(t, "SaveTaskData")
+	mockRepo.AssertNotCalled(t, "GetOrCreateTaskTypeID")
+	mockStatus.AssertNotCalled(t, "SaveProcessedDate")
+}
+
+func TestProcessDate_MemoryStore(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	store := memory.New()
+	mockHermes := mocks.NewScraperServiceClient(t)
+	service := NewTaskService(logger, store, store, metrics.NewMetrics(prometheus.NewRegistry()), mockHermes)
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	task := &pb.Task{Id: 1, Type: "Repair", Description: "broken cable", Executors: []string{"Doe J."}}
+
+	t.Run("saves new tasks and moves the cursor", func(t *testing.T) {
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_1", Tasks: []*pb.Task{task}}, nil).Once()
+
+		_, err := service.processDate(t.Context(), day)
+		require.NoError(t, err)
+
+		stored, ok := store.Task(1)
+		require.True(t, ok)
+		require.Equal(t, "broken cable", stored.Description)
+		require.Equal(t, []string{"Doe J."}, stored.Executors)
+
+		lastDate, err := service.GetLastDate(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, day.AddDate(0, 0, 1), lastDate)
+	})
+
+	t.Run("skips unchanged data", func(t *testing.T) {
+		writes := store.Writes()
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_1"}, nil).Once()
+
+		_, err := service.syncDate(t.Context(), day)
+		require.NoError(t, err)
+
+		require.Equal(t, writes, store.Writes())
+	})
+
+	t.Run("updates a changed task", func(t *testing.T) {
+		closed := &pb.Task{Id: 1, Type: "Repair", Description: "cable replaced", IsClosed: true}
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_2", Tasks: []*pb.Task{closed}}, nil).Once()
+
+		_, err := service.syncDate(t.Context(), day)
+		require.NoError(t, err)
+
+		stored, _ := store.Task(1)
+		require.True(t, stored.IsClosed)
+		require.Equal(t, "cable replaced", stored.Description)
+		require.Empty(t, stored.Executors)
+	})
+}
+
+func TestConvertPbTasksToModels_NormalizesUnicode(t *testing.T) {
+	// "café" with a precomposed "é" and with "e" + combining acute accent.
+	composed := "caf\u00e9"
+	decomposed := "cafe\u0301"
+	require.NotEqual(t, composed, decomposed)
+
+	task := func(text string) *pb.Task {
+		return &pb.Task{
+			Id: 1, Type: text, Description: text, Address: text, CustomerName: text, CustomerLogin: text,
+			Comments: []string{text}, Executors: []string{text},
+		}
+	}
+
+	fromComposed := convertPbTasksToModels([]*pb.Task{task(composed)})
+	fromDecomposed := convertPbTasksToModels([]*pb.Task{task(decomposed)})
+
+	require.Equal(t, fromComposed, fromDecomposed)
+	require.Equal(t, []string{composed}, fromDecomposed[0].Executors)
+}
+
+func TestSyncDate_IngestOnlyClosed(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	openTask := &pb.Task{Id: 1, Type: "Repair"}
+	closedTask := &pb.Task{Id: 2, Type: "Repair", ClosingDate: timestamppb.New(day.Add(15 * time.Hour)), IsClosed: true}
+
+	tests := []struct {
+		name         string
+		onlyClosed   bool
+		wantSaved    []int
+		wantFiltered float64
+	}{
+		{name: "skips open tasks when enabled", onlyClosed: true, wantSaved: []int{2}, wantFiltered: 1},
+		{name: "saves all tasks when disabled", onlyClosed: false, wantSaved: []int{1, 2}, wantFiltered: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+			testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+			store := memory.New()
+			mockHermes := mocks.NewScraperServiceClient(t)
+			service := NewTaskService(logger, store, store, testMetrics, mockHermes, WithIngestOnlyClosed(tt.onlyClosed))
+
+			mockHermes.On("GetDailyTasks", mock.Anything, mock.Anything).
+				Return(&pb.GetDailyTasksResponse{NewHash: "hash", Tasks: []*pb.Task{openTask, closedTask}}, nil).
+				Once()
+
+			_, err := service.syncDate(t.Context(), day)
+			require.NoError(t, err)
+
+			var saved []int
+			for _, id := range []int{1, 2} {
+				if _, ok := store.Task(id); ok {
+					saved = append(saved, id)
+				}
+			}
+			require.Equal(t, tt.wantSaved, saved)
+			require.InDelta(t, tt.wantFiltered,
+				testutil.ToFloat64(testMetrics.FilteredItems.WithLabelValues("task", "open")), 0)
+		})
+	}
+}
+
+func TestProcessDate_DataUnchangedSeconds(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	testMetrics := metrics.NewMetrics(prometheus.NewRegistry())
+	store := memory.New()
+	mockHermes := mocks.NewScraperServiceClient(t)
+	service := NewTaskService(logger, store, store, testMetrics, mockHermes)
+	gauge := testMetrics.DataUnchangedSeconds.WithLabelValues("task")
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+		Return(&pb.GetDailyTasksResponse{NewHash: "hash_1", Tasks: []*pb.Task{{Id: 1, Type: "Repair"}}}, nil).Once()
+	mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+		Return(&pb.GetDailyTasksResponse{NewHash: "hash_1"}, nil).Once()
+
+	_, err := service.processDate(t.Context(), day)
+	require.NoError(t, err)
+	require.InDelta(t, 0, testutil.ToFloat64(gauge), 0)
+
+	now = now.Add(90 * time.Minute)
+	_, err = service.processDate(t.Context(), day)
+	require.NoError(t, err)
+	require.InDelta(t, (90 * time.Minute).Seconds(), testutil.ToFloat64(gauge), 0)
+}
+
+func TestProcessDate_TasksProcessed(t *testing.T) {
+	service, mockRepo, mockStatus, mockHermes := newTestTaskService(t)
+	processed := service.metrics.TasksProcessed
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []*pb.Task{{Id: 1, Type: "Repair"}, {Id: 2, Type: "Repair"}}
+
+	t.Run("counts saved tasks", func(t *testing.T) {
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_1", Tasks: tasks}, nil).Once()
+		mockRepo.On("SaveTaskData", mock.Anything, mock.AnythingOfType("models.Task")).Return(nil).Twice()
+		mockStatus.On("SaveProcessedDate", mock.Anything, day.AddDate(0, 0, 1)).Return(nil).Once()
+
+		_, err := service.processDate(t.Context(), day)
+		require.NoError(t, err)
+
+		require.InDelta(t, 2, testutil.ToFloat64(processed.WithLabelValues("saved")), 0)
+	})
+
+	t.Run("counts tasks skipped on hash match", func(t *testing.T) {
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_1", Tasks: tasks}, nil).Once()
+		mockStatus.On("SaveProcessedDate", mock.Anything, day.AddDate(0, 0, 1)).Return(nil).Once()
+
+		_, err := service.processDate(t.Context(), day)
+		require.NoError(t, err)
+
+		require.InDelta(t, 2, testutil.ToFloat64(processed.WithLabelValues("skipped")), 0)
+		require.InDelta(t, 2, testutil.ToFloat64(processed.WithLabelValues("saved")), 0)
+	})
+
+	t.Run("counts a failed save", func(t *testing.T) {
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_2", Tasks: tasks}, nil).Once()
+		mockRepo.On("SaveTaskData", mock.Anything, mock.AnythingOfType("models.Task")).
+			Return(errors.New("connection refused")).Once()
+
+		_, err := service.processDate(t.Context(), day)
+		require.Error(t, err)
+
+		require.InDelta(t, 1, testutil.ToFloat64(processed.WithLabelValues("failed")), 0)
+		require.InDelta(t, 2, testutil.ToFloat64(processed.WithLabelValues("saved")), 0)
+	})
+}
+
+func TestProcessDate_Progress(t *testing.T) {
+	service, mockRepo, mockStatus, mockHermes := newTestTaskService(t)
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	hash, lastSuccess := service.Progress()
+	require.Empty(t, hash)
+	require.True(t, lastSuccess.IsZero())
+
+	mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+		Return(&pb.GetDailyTasksResponse{NewHash: "hash_1", Tasks: []*pb.Task{{Id: 1, Type: "Repair"}}}, nil).Once()
+	mockRepo.On("SaveTaskData", mock.Anything, mock.AnythingOfType("models.Task")).Return(nil).Once()
+	mockStatus.On("SaveProcessedDate", mock.Anything, day.AddDate(0, 0, 1)).Return(nil).Once()
+
+	_, err := service.processDate(t.Context(), day)
+	require.NoError(t, err)
+
+	hash, lastSuccess = service.Progress()
+	require.Equal(t, "hash_1", hash)
+	require.Equal(t, now, lastSuccess)
+}
+
+func TestProcessDate_CursorOnlyAdvancesAfterPersistence(t *testing.T) {
+	service, mockRepo, mockStatus, mockHermes := newTestTaskService(t)
+	service.lastKnownHash = "old_hash"
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []*pb.Task{{Id: 1, Type: "Repair"}, {Id: 2, Type: "Repair"}, {Id: 3, Type: "Repair"}}
+	isTask := func(id int) any {
+		return mock.MatchedBy(func(task models.Task) bool { return task.ID == id })
+	}
+
+	t.Run("a failed save mid-loop leaves the cursor and hash unchanged", func(t *testing.T) {
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "new_hash", Tasks: tasks}, nil).Once()
+		mockRepo.On("SaveTaskData", mock.Anything, isTask(1)).Return(nil).Once()
+		mockRepo.On("SaveTaskData", mock.Anything, isTask(2)).Return(errors.New("connection reset")).Once()
+
+		_, err := service.processDate(t.Context(), day)
+
+		require.ErrorContains(t, err, "failed to save task '2'")
+		mockStatus.AssertNotCalled(t, "SaveProcessedDate", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "SaveTaskData", mock.Anything, isTask(3))
+		require.Equal(t, "old_hash", service.lastKnownHash)
+	})
+
+	t.Run("the retry stores the whole day and advances the cursor", func(t *testing.T) {
+		mockHermes.On("GetDailyTasks", mock.Anything, mock.MatchedBy(func(req *pb.GetDailyTasksRequest) bool {
+			return req.GetKnownHash() == "old_hash"
+		})).Return(&pb.GetDailyTasksResponse{NewHash: "new_hash", Tasks: tasks}, nil).Once()
+		mockRepo.On("SaveTaskData", mock.Anything, mock.AnythingOfType("models.Task")).Return(nil).Times(3)
+		mockStatus.On("SaveProcessedDate", mock.Anything, day.AddDate(0, 0, 1)).Return(nil).Once()
+
+		_, err := service.processDate(t.Context(), day)
+		require.NoError(t, err)
+
+		require.Equal(t, "new_hash", service.lastKnownHash)
+		mockStatus.AssertExpectations(t)
+	})
+}
+
+func TestProcessDate_DeadLetter(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []*pb.Task{{Id: 1, Type: "Repair"}, {Id: 2, Type: "Repair"}}
+	isTask := func(id int) any {
+		return mock.MatchedBy(func(task models.Task) bool { return task.ID == id })
+	}
+
+	t.Run("a failure without the queue fails the run", func(t *testing.T) {
+		service, mockRepo, mockStatus, mockHermes := newTestTaskService(t)
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_1", Tasks: tasks}, nil).Once()
+		mockRepo.On("SaveTaskData", mock.Anything, isTask(1)).Return(errors.New("value too long")).Once()
+
+		_, err := service.processDate(t.Context(), day)
+
+		require.ErrorContains(t, err, "failed to save task '1'")
+		mockStatus.AssertNotCalled(t, "SaveProcessedDate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("a transient failure is retried within the run", func(t *testing.T) {
+		service, mockRepo, mockStatus, mockHermes := newTestTaskService(t)
+		deadLetter := mocks.NewFailedTaskRepoIface(t)
+		WithDeadLetter(deadLetter, 3)(service)
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_1", Tasks: tasks}, nil).Once()
+		mockRepo.On("SaveTaskData", mock.Anything, isTask(1)).Return(errors.New("deadlock detected")).Once()
+		mockRepo.On("SaveTaskData", mock.Anything, isTask(1)).Return(nil).Once()
+		mockRepo.On("SaveTaskData", mock.Anything, isTask(2)).Return(nil).Once()
+		mockStatus.On("SaveProcessedDate", mock.Anything, day.AddDate(0, 0, 1)).Return(nil).Once()
+
+		summary, err := service.processDate(t.Context(), day)
+
+		require.NoError(t, err)
+		require.Equal(t, 2, summary.Saved)
+		deadLetter.AssertNotCalled(t, "SaveFailedTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("the task is dead-lettered at the limit and the day advances", func(t *testing.T) {
+		service, mockRepo, mockStatus, mockHermes := newTestTaskService(t)
+		deadLetter := mocks.NewFailedTaskRepoIface(t)
+		WithDeadLetter(deadLetter, 2)(service)
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_1", Tasks: tasks}, nil).Once()
+		mockRepo.On("SaveTaskData", mock.Anything, isTask(1)).Return(errors.New("value too long")).Twice()
+		deadLetter.On("SaveFailedTask", mock.Anything, isTask(1), 2, "value too long").Return(nil).Once()
+		mockRepo.On("SaveTaskData", mock.Anything, isTask(2)).Return(nil).Once()
+		mockStatus.On("SaveProcessedDate", mock.Anything, day.AddDate(0, 0, 1)).Return(nil).Once()
+
+		summary, err := service.processDate(t.Context(), day)
+		require.NoError(t, err)
+
+		require.Equal(t, 1, summary.Saved)
+		require.InDelta(t, 0, testutil.ToFloat64(service.metrics.TasksProcessed.WithLabelValues("failed")), 0)
+		require.InDelta(t, 1, testutil.ToFloat64(service.metrics.TasksProcessed.WithLabelValues("dead_lettered")), 0)
+		mockStatus.AssertExpectations(t)
+	})
+
+	t.Run("a dead-letter failure fails the run", func(t *testing.T) {
+		service, mockRepo, mockStatus, mockHermes := newTestTaskService(t)
+		deadLetter := mocks.NewFailedTaskRepoIface(t)
+		WithDeadLetter(deadLetter, 2)(service)
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_1", Tasks: tasks}, nil).Once()
+		mockRepo.On("SaveTaskData", mock.Anything, isTask(1)).Return(errors.New("value too long")).Twice()
+		deadLetter.On("SaveFailedTask", mock.Anything, isTask(1), 2, "value too long").
+			Return(errors.New("connection refused")).Once()
+
+		_, err := service.processDate(t.Context(), day)
+
+		require.ErrorContains(t, err, "failed to dead-letter task '1'")
+		require.InDelta(t, 1, testutil.ToFloat64(service.metrics.TasksProcessed.WithLabelValues("failed")), 0)
+		mockStatus.AssertNotCalled(t, "SaveProcessedDate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCatchUpToNow_DeadLetter(t *testing.T) {
+	// Catch-up covers 2024-03-01 and 2024-03-02, today is left to the maintenance loop.
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	service, mockRepo, mockStatus, mockHermes := newTestTaskService(t)
+	deadLetter := mocks.NewFailedTaskRepoIface(t)
+	WithDeadLetter(deadLetter, 3)(service)
+	WithCatchupSkipToday(true)(service)
+	service.now = func() time.Time { return time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC) }
+	isTask := func(id int) any {
+		return mock.MatchedBy(func(task models.Task) bool { return task.ID == id })
+	}
+
+	mockStatus.On("GetLastProcessedDate", mock.Anything).Return(start, nil).Once()
+	mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+		Return(&pb.GetDailyTasksResponse{NewHash: "hash_1", Tasks: []*pb.Task{{Id: 1}, {Id: 2}}}, nil).Once()
+	mockRepo.On("SaveTaskData", mock.Anything, isTask(1)).Return(errors.New("value too long"))
+	mockRepo.On("SaveTaskData", mock.Anything, isTask(2)).Return(nil).Once()
+	deadLetter.On("SaveFailedTask", mock.Anything, isTask(1), 3, "value too long").Return(nil).Once()
+	mockStatus.On("SaveProcessedDate", mock.Anything, start.AddDate(0, 0, 1)).Return(nil).Once()
+
+	mockStatus.On("GetLastProcessedDate", mock.Anything).Return(start.AddDate(0, 0, 1), nil).Once()
+	mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-02")).
+		Return(&pb.GetDailyTasksResponse{NewHash: "hash_2"}, nil).Once()
+	mockStatus.On("SaveProcessedDate", mock.Anything, start.AddDate(0, 0, 2)).Return(nil).Once()
+
+	mockStatus.On("GetLastProcessedDate", mock.Anything).Return(start.AddDate(0, 0, 2), nil).Once()
+	mockRepo.On("AnalyzeTables", mock.Anything, "tasks", "task_executors").Return(nil).Once()
+
+	require.NoError(t, service.catchUpToNow(t.Context()))
+
+	mockRepo.AssertNumberOfCalls(t, "SaveTaskData", 4)
+	mockStatus.AssertExpectations(t)
+	deadLetter.AssertExpectations(t)
+}
+
+func TestRetryFailedTasks(t *testing.T) {
+	service, mockRepo, _, _ := newTestTaskService(t)
+	deadLetter := mocks.NewFailedTaskRepoIface(t)
+	WithDeadLetter(deadLetter, 3)(service)
+
+	deadLetter.On("ListFailedTasks", mock.Anything).Return([]models.FailedTask{
+		{Task: models.Task{ID: 1}, Attempts: 3, Error: "value too long"},
+		{Task: models.Task{ID: 2}, Attempts: 3, Error: "deadlock detected"},
+	}, nil).Once()
+	mockRepo.On("SaveTaskData", mock.Anything, models.Task{ID: 1}).Return(errors.New("value too long")).Once()
+	mockRepo.On("SaveTaskData", mock.Anything, models.Task{ID: 2}).Return(nil).Once()
+	deadLetter.On("DeleteFailedTask", mock.Anything, 2).Return(nil).Once()
+
+	retried, err := service.RetryFailedTasks(t.Context())
+
+	require.NoError(t, err)
+	require.Equal(t, 1, retried)
+	deadLetter.AssertNotCalled(t, "DeleteFailedTask", mock.Anything, 1)
+}
+
+func TestCatchUpParallel(t *testing.T) {
+	// Catch-up covers 2024-03-01 to 2024-03-04, today is left to the maintenance loop.
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	newService := func(t *testing.T) (*TaskService, *mocks.TaskRepoIface, *mocks.StatusRepoIface,
+		*mocks.ScraperServiceClient,
+	) {
+		t.Helper()
+
+		service, mockRepo, mockStatus, mockHermes := newTestTaskService(t)
+		WithCatchupWorkers(4)(service)
+		WithCatchupSkipToday(true)(service)
+		service.now = func() time.Time { return now }
+		mockStatus.On("GetLastProcessedDate", mock.Anything).Return(start, nil).Once()
+
+		return service, mockRepo, mockStatus, mockHermes
+	}
+
+	t.Run("advances the cursor in order when days finish out of order", func(t *testing.T) {
+		service, mockRepo, mockStatus, mockHermes := newService(t)
+		lastDone := make(chan struct{})
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Run(func(_ mock.Arguments) { <-lastDone }).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_1"}, nil).Once()
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-02")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_2"}, nil).Once()
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-03")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_3"}, nil).Once()
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-04")).
+			Run(func(_ mock.Arguments) { close(lastDone) }).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_4"}, nil).Once()
+
+		var cursors []time.Time
+		mockStatus.On("SaveProcessedDate", mock.Anything, mock.AnythingOfType("time.Time")).
+			Run(func(args mock.Arguments) { cursors = append(cursors, args.Get(1).(time.Time)) }).
+			Return(nil).Times(4)
+		mockStatus.On("GetLastProcessedDate", mock.Anything).Return(start.AddDate(0, 0, 4), nil).Once()
+		mockRepo.On("AnalyzeTables", mock.Anything, "tasks", "task_executors").Return(nil).Once()
+
+		require.NoError(t, service.catchUpToNow(t.Context()))
+
+		require.Equal(t, []time.Time{
+			start.AddDate(0, 0, 1), start.AddDate(0, 0, 2), start.AddDate(0, 0, 3), start.AddDate(0, 0, 4),
+		}, cursors)
+	})
+
+	t.Run("does not share the rolling hash between concurrent days", func(t *testing.T) {
+		service, mockRepo, mockStatus, mockHermes := newService(t)
+		service.lastKnownHash = "hash_before"
+		mockHermes.On("GetDailyTasks", mock.Anything, mock.MatchedBy(func(req *pb.GetDailyTasksRequest) bool {
+			return req.GetKnownHash() == ""
+		})).Return(&pb.GetDailyTasksResponse{NewHash: "hash_day"}, nil).Times(4)
+		mockStatus.On("SaveProcessedDate", mock.Anything, mock.AnythingOfType("time.Time")).Return(nil).Times(4)
+		mockStatus.On("GetLastProcessedDate", mock.Anything).Return(start.AddDate(0, 0, 4), nil).Once()
+		mockRepo.On("AnalyzeTables", mock.Anything, "tasks", "task_executors").Return(nil).Once()
+
+		require.NoError(t, service.catchUpToNow(t.Context()))
+
+		require.Equal(t, "hash_before", service.lastKnownHash)
+	})
+
+	t.Run("uses the per-date hash of every day", func(t *testing.T) {
+		service, mockRepo, mockStatus, mockHermes := newService(t)
+		dateHashes := mocks.NewDateHashRepoIface(t)
+		WithDateHashes(dateHashes)(service)
+		for day := range 4 {
+			date := start.AddDate(0, 0, day)
+			key := date.Format("2006-01-02")
+			dateHashes.On("GetDateHash", mock.Anything, date).Return("hash_"+key, nil).Once()
+			mockHermes.On("GetDailyTasks", mock.Anything, mock.MatchedBy(func(req *pb.GetDailyTasksRequest) bool {
+				return req.GetDate().GetValue() == key && req.GetKnownHash() == "hash_"+key
+			})).Return(&pb.GetDailyTasksResponse{NewHash: "hash_" + key}, nil).Once()
+		}
+		mockStatus.On("SaveProcessedDate", mock.Anything, mock.AnythingOfType("time.Time")).Return(nil).Times(4)
+		mockStatus.On("GetLastProcessedDate", mock.Anything).Return(start.AddDate(0, 0, 4), nil).Once()
+		mockRepo.On("AnalyzeTables", mock.Anything, "tasks", "task_executors").Return(nil).Once()
+
+		require.NoError(t, service.catchUpToNow(t.Context()))
+
+		mockHermes.AssertExpectations(t)
+	})
+
+	t.Run("a failed middle day stops the cursor before it", func(t *testing.T) {
+		service, _, mockStatus, mockHermes := newService(t)
+		// The second day fails once the days after it are in flight, and the first day
+		// finishes only after that.
+		var later sync.WaitGroup
+		later.Add(2)
+		secondFailed := make(chan struct{})
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Run(func(_ mock.Arguments) { <-secondFailed }).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_1"}, nil).Once()
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-02")).
+			Run(func(_ mock.Arguments) {
+				later.Wait()
+				close(secondFailed)
+			}).
+			Return(nil, errors.New("hermes unavailable")).Once()
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-03")).
+			Run(func(_ mock.Arguments) { later.Done() }).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_3"}, nil).Once()
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-04")).
+			Run(func(_ mock.Arguments) { later.Done() }).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_4"}, nil).Once()
+		mockStatus.On("SaveProcessedDate", mock.Anything, start.AddDate(0, 0, 1)).Return(nil).Once()
+
+		err := service.catchUpToNow(t.Context())
+
+		require.ErrorContains(t, err, "failed to process date 2024-03-02 during catch-up")
+		require.ErrorContains(t, err, "hermes unavailable")
+		mockStatus.AssertNumberOfCalls(t, "SaveProcessedDate", 1)
+		// The days synced past the failed one are still recorded as successful runs.
+		require.InDelta(t, 3, testutil.ToFloat64(service.metrics.Runs.WithLabelValues("success")), 0)
+		require.InDelta(t, 1, testutil.ToFloat64(service.metrics.Runs.WithLabelValues("failure")), 0)
+	})
+
+	t.Run("stops on cancellation without moving the cursor", func(t *testing.T) {
+		service, _, mockStatus, mockHermes := newService(t)
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Run(func(_ mock.Arguments) { cancel() }).
+			Return(nil, context.Canceled).Once()
+		mockHermes.On("GetDailyTasks", mock.Anything, mock.Anything).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash"}, nil).Maybe()
+
+		err := service.catchUpToNow(ctx)
+
+		require.ErrorIs(t, err, context.Canceled)
+		mockStatus.AssertNotCalled(t, "SaveProcessedDate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSyncDate_TaskObserver(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	store := memory.New()
+	mockHermes := mocks.NewScraperServiceClient(t)
+	observer := mocks.NewTaskObserver(t)
+	service := NewTaskService(logger, store, store, metrics.NewMetrics(prometheus.NewRegistry()), mockHermes,
+		WithTaskObserver(observer))
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	respond := func(hash string, tasks ...*pb.Task) {
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: hash, Tasks: tasks}, nil).Once()
+	}
+
+	t.Run("new tasks are not reported, even when closed", func(t *testing.T) {
+		respond("hash_1", &pb.Task{Id: 1, Type: "Repair"}, &pb.Task{Id: 2, Type: "Repair", IsClosed: true})
+
+		_, err := service.syncDate(t.Context(), day)
+		require.NoError(t, err)
+
+		observer.AssertNotCalled(t, "OnTaskClosed", mock.Anything, mock.Anything)
+	})
+
+	t.Run("an open task getting closed is reported", func(t *testing.T) {
+		respond("hash_2", &pb.Task{Id: 1, Type: "Repair", IsClosed: true}, &pb.Task{Id: 2, Type: "Repair", IsClosed: true})
+		observer.On("OnTaskClosed", mock.Anything, mock.MatchedBy(func(task models.Task) bool {
+			return task.ID == 1 && task.IsClosed
+		})).Once()
+
+		_, err := service.syncDate(t.Context(), day)
+		require.NoError(t, err)
+
+		observer.AssertNumberOfCalls(t, "OnTaskClosed", 1)
+	})
+
+	t.Run("already closed tasks are not reported again", func(t *testing.T) {
+		respond("hash_3", &pb.Task{Id: 1, Type: "Repair", IsClosed: true, Description: "cable replaced"})
+
+		_, err := service.syncDate(t.Context(), day)
+		require.NoError(t, err)
+
+		observer.AssertNumberOfCalls(t, "OnTaskClosed", 1)
+	})
+}
+
+func TestUpdateTaskTypes(t *testing.T) {
+	t.Run("saves every type", func(t *testing.T) {
+		service, mockRepo, _, mockHermes := newTestTaskService(t)
+		mockHermes.On("GetTaskTypes", mock.Anything, mock.Anything).
+			Return(&pb.GetTaskTypesResponse{Types: []string{"Repair", "Install"}}, nil).Once()
+		mockRepo.On("GetOrCreateTaskTypeID", mock.Anything, "Repair").Return(1, nil).Once()
+		mockRepo.On("GetOrCreateTaskTypeID", mock.Anything, "Install").Return(2, nil).Once()
+
+		require.NoError(t, service.updateTaskTypes(t.Context()))
+	})
+
+	t.Run("hermes error", func(t *testing.T) {
+		service, mockRepo, _, mockHermes := newTestTaskService(t)
+		mockHermes.On("GetTaskTypes", mock.Anything, mock.Anything).
+			Return(nil, errors.New("hermes unavailable")).Once()
+
+		err := service.updateTaskTypes(t.Context())
+
+		require.ErrorContains(t, err, "failed to get task types from Hermes")
+		mockRepo.AssertNotCalled(t, "GetOrCreateTaskTypeID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("stops at the first type that fails to save", func(t *testing.T) {
+		service, mockRepo, _, mockHermes := newTestTaskService(t)
+		mockHermes.On("GetTaskTypes", mock.Anything, mock.Anything).
+			Return(&pb.GetTaskTypesResponse{Types: []string{"Repair", "Install"}}, nil).Once()
+		mockRepo.On("GetOrCreateTaskTypeID", mock.Anything, "Repair").Return(0, errors.New("db error")).Once()
+
+		err := service.updateTaskTypes(t.Context())
+
+		require.ErrorContains(t, err, "failed to save task name 'Repair'")
+		mockRepo.AssertNotCalled(t, "GetOrCreateTaskTypeID", mock.Anything, "Install")
+	})
+}
+
+func TestProcessDate_Summary(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	store := memory.New()
+	mockHermes := mocks.NewScraperServiceClient(t)
+	service := NewTaskService(logger, store, store, metrics.NewMetrics(prometheus.NewRegistry()), mockHermes,
+		WithIngestOnlyClosed(true))
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	closedAt := timestamppb.New(day.Add(12 * time.Hour))
+	tasks := []*pb.Task{
+		{Id: 1, Type: "Repair", ClosingDate: closedAt, IsClosed: true},
+		{Id: 2, Type: "Repair", ClosingDate: closedAt, IsClosed: true},
+		{Id: 3, Type: "Repair"},
+	}
+
+	t.Run("counts saved and filtered tasks", func(t *testing.T) {
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-01")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_1", Tasks: tasks}, nil).Once()
+
+		summary, err := service.processDate(t.Context(), day)
+
+		require.NoError(t, err)
+		require.Equal(t, DateSummary{Date: day, Saved: 2, Skipped: 1}, summary)
+	})
+
+	t.Run("counts every task of an unchanged date as skipped", func(t *testing.T) {
+		mockHermes.On("GetDailyTasks", mock.Anything, dailyTasksForDate("2024-03-02")).
+			Return(&pb.GetDailyTasksResponse{NewHash: "hash_1", Tasks: tasks}, nil).Once()
+
+		summary, err := service.processDate(t.Context(), day.AddDate(0, 0, 1))
+
+		require.NoError(t, err)
+		require.Equal(t, DateSummary{Date: day.AddDate(0, 0, 1), Skipped: 3}, summary)
+	})
+
+	require.Equal(t, map[string]int{"saved": 2, "skipped": 4}, service.Counts())
+	require.InDelta(t, 4, testutil.ToFloat64(service.metrics.TasksProcessed.WithLabelValues("skipped")), 0)
+}
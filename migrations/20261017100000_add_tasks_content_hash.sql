@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS content_hash TEXT;
+CREATE INDEX IF NOT EXISTS idx_tasks_content_hash_null ON tasks (task_id) WHERE content_hash IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tasks_content_hash_null;
+ALTER TABLE tasks DROP COLUMN IF EXISTS content_hash;
+-- +goose StatementEnd
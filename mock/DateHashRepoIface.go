@@ -0,0 +1,76 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// DateHashRepoIface is an autogenerated mock type for the DateHashRepoIface type
+type DateHashRepoIface struct {
+	mock.Mock
+}
+
+// GetDateHash provides a mock function with given fields: ctx, date
+func (_m *DateHashRepoIface) GetDateHash(ctx context.Context, date time.Time) (string, error) {
+	ret := _m.Called(ctx, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDateHash")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (string, error)); ok {
+		return rf(ctx, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) string); ok {
+		r0 = rf(ctx, date)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveDateHash provides a mock function with given fields: ctx, date, hash
+func (_m *DateHashRepoIface) SaveDateHash(ctx context.Context, date time.Time, hash string) error {
+	ret := _m.Called(ctx, date, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveDateHash")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string) error); ok {
+		r0 = rf(ctx, date, hash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDateHashRepoIface creates a new instance of DateHashRepoIface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDateHashRepoIface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DateHashRepoIface {
+	mock := &DateHashRepoIface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
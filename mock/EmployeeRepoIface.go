@@ -14,6 +14,36 @@ type EmployeeRepoIface struct {
 	mock.Mock
 }
 
+// GetActiveEmployeeIDs provides a mock function with given fields: ctx
+func (_m *EmployeeRepoIface) GetActiveEmployeeIDs(ctx context.Context) ([]int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActiveEmployeeIDs")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []int); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetEmployeeByID provides a mock function with given fields: ctx, identifier
 func (_m *EmployeeRepoIface) GetEmployeeByID(ctx context.Context, identifier int) (models.Employee, error) {
 	ret := _m.Called(ctx, identifier)
@@ -42,6 +72,54 @@ func (_m *EmployeeRepoIface) GetEmployeeByID(ctx context.Context, identifier int
 	return r0, r1
 }
 
+// GetEmployeesByPosition provides a mock function with given fields: ctx, position
+func (_m *EmployeeRepoIface) GetEmployeesByPosition(ctx context.Context, position string) ([]models.Employee, error) {
+	ret := _m.Called(ctx, position)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEmployeesByPosition")
+	}
+
+	var r0 []models.Employee
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.Employee, error)); ok {
+		return rf(ctx, position)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.Employee); ok {
+		r0 = rf(ctx, position)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Employee)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkEmployeeDismissed provides a mock function with given fields: ctx, identifier
+func (_m *EmployeeRepoIface) MarkEmployeeDismissed(ctx context.Context, identifier int) error {
+	ret := _m.Called(ctx, identifier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEmployeeDismissed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, identifier)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveEmployee provides a mock function with given fields: ctx, identifier, fullname, shortname, position, email, phone
 func (_m *EmployeeRepoIface) SaveEmployee(ctx context.Context, identifier int, fullname string, shortname string, position string, email string, phone string) error {
 	ret := _m.Called(ctx, identifier, fullname, shortname, position, email, phone)
@@ -78,6 +156,24 @@ func (_m *EmployeeRepoIface) UpdateEmployee(ctx context.Context, identifier int,
 	return r0
 }
 
+// UpsertEmployees provides a mock function with given fields: ctx, employees
+func (_m *EmployeeRepoIface) UpsertEmployees(ctx context.Context, employees []models.Employee) error {
+	ret := _m.Called(ctx, employees)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertEmployees")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Employee) error); ok {
+		r0 = rf(ctx, employees)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewEmployeeRepoIface creates a new instance of EmployeeRepoIface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewEmployeeRepoIface(t interface {
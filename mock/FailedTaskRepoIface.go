@@ -0,0 +1,95 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/UnknownOlympus/hephaestus/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FailedTaskRepoIface is an autogenerated mock type for the FailedTaskRepoIface type
+type FailedTaskRepoIface struct {
+	mock.Mock
+}
+
+// DeleteFailedTask provides a mock function with given fields: ctx, taskID
+func (_m *FailedTaskRepoIface) DeleteFailedTask(ctx context.Context, taskID int) error {
+	ret := _m.Called(ctx, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteFailedTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, taskID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListFailedTasks provides a mock function with given fields: ctx
+func (_m *FailedTaskRepoIface) ListFailedTasks(ctx context.Context) ([]models.FailedTask, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFailedTasks")
+	}
+
+	var r0 []models.FailedTask
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.FailedTask, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.FailedTask); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.FailedTask)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveFailedTask provides a mock function with given fields: ctx, task, attempts, cause
+func (_m *FailedTaskRepoIface) SaveFailedTask(ctx context.Context, task models.Task, attempts int, cause string) error {
+	ret := _m.Called(ctx, task, attempts, cause)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveFailedTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Task, int, string) error); ok {
+		r0 = rf(ctx, task, attempts, cause)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFailedTaskRepoIface creates a new instance of FailedTaskRepoIface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFailedTaskRepoIface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FailedTaskRepoIface {
+	mock := &FailedTaskRepoIface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,76 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// QuarantineRepoIface is an autogenerated mock type for the QuarantineRepoIface type
+type QuarantineRepoIface struct {
+	mock.Mock
+}
+
+// GetOldestQuarantinedAt provides a mock function with given fields: ctx, itemType
+func (_m *QuarantineRepoIface) GetOldestQuarantinedAt(ctx context.Context, itemType string) (time.Time, error) {
+	ret := _m.Called(ctx, itemType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOldestQuarantinedAt")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (time.Time, error)); ok {
+		return rf(ctx, itemType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) time.Time); ok {
+		r0 = rf(ctx, itemType)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, itemType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceQuarantined provides a mock function with given fields: ctx, itemType, items
+func (_m *QuarantineRepoIface) ReplaceQuarantined(ctx context.Context, itemType string, items map[int]string) error {
+	ret := _m.Called(ctx, itemType, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceQuarantined")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[int]string) error); ok {
+		r0 = rf(ctx, itemType, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewQuarantineRepoIface creates a new instance of QuarantineRepoIface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewQuarantineRepoIface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *QuarantineRepoIface {
+	mock := &QuarantineRepoIface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
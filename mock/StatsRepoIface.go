@@ -0,0 +1,84 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StatsRepoIface is an autogenerated mock type for the StatsRepoIface type
+type StatsRepoIface struct {
+	mock.Mock
+}
+
+// CountEmployees provides a mock function with given fields: ctx
+func (_m *StatsRepoIface) CountEmployees(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountEmployees")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountTasks provides a mock function with given fields: ctx, onlyOpen
+func (_m *StatsRepoIface) CountTasks(ctx context.Context, onlyOpen bool) (int, error) {
+	ret := _m.Called(ctx, onlyOpen)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountTasks")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool) (int, error)); ok {
+		return rf(ctx, onlyOpen)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, bool) int); ok {
+		r0 = rf(ctx, onlyOpen)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = rf(ctx, onlyOpen)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStatsRepoIface creates a new instance of StatsRepoIface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStatsRepoIface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StatsRepoIface {
+	mock := &StatsRepoIface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,34 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/UnknownOlympus/hephaestus/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaskObserver is an autogenerated mock type for the TaskObserver type
+type TaskObserver struct {
+	mock.Mock
+}
+
+// OnTaskClosed provides a mock function with given fields: ctx, task
+func (_m *TaskObserver) OnTaskClosed(ctx context.Context, task models.Task) {
+	_m.Called(ctx, task)
+}
+
+// NewTaskObserver creates a new instance of TaskObserver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTaskObserver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TaskObserver {
+	mock := &TaskObserver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
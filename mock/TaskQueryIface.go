@@ -0,0 +1,157 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/UnknownOlympus/hephaestus/internal/models"
+	mock "github.com/stretchr/testify/mock"
+
+	repository "github.com/UnknownOlympus/hephaestus/internal/repository"
+
+	time "time"
+)
+
+// TaskQueryIface is an autogenerated mock type for the TaskQueryIface type
+type TaskQueryIface struct {
+	mock.Mock
+}
+
+// GetStuckTasks provides a mock function with given fields: ctx, olderThan
+func (_m *TaskQueryIface) GetStuckTasks(ctx context.Context, olderThan time.Duration) ([]models.Task, error) {
+	ret := _m.Called(ctx, olderThan)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStuckTasks")
+	}
+
+	var r0 []models.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) ([]models.Task, error)); ok {
+		return rf(ctx, olderThan)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) []models.Task); ok {
+		r0 = rf(ctx, olderThan)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaskByID provides a mock function with given fields: ctx, id
+func (_m *TaskQueryIface) GetTaskByID(ctx context.Context, id int) (models.Task, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskByID")
+	}
+
+	var r0 models.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.Task, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.Task); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTasks provides a mock function with given fields: ctx, filter
+func (_m *TaskQueryIface) ListTasks(ctx context.Context, filter repository.TaskFilter) ([]models.Task, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTasks")
+	}
+
+	var r0 []models.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.TaskFilter) ([]models.Task, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.TaskFilter) []models.Task); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.TaskFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StreamTasksByDateRange provides a mock function with given fields: ctx, from, to, fn
+func (_m *TaskQueryIface) StreamTasksByDateRange(ctx context.Context, from time.Time, to time.Time, fn func(models.Task) error) error {
+	ret := _m.Called(ctx, from, to, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamTasksByDateRange")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, func(models.Task) error) error); ok {
+		r0 = rf(ctx, from, to, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StreamTasksByExecutor provides a mock function with given fields: ctx, shortname, fn
+func (_m *TaskQueryIface) StreamTasksByExecutor(ctx context.Context, shortname string, fn func(models.Task) error) error {
+	ret := _m.Called(ctx, shortname, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamTasksByExecutor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, func(models.Task) error) error); ok {
+		r0 = rf(ctx, shortname, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTaskQueryIface creates a new instance of TaskQueryIface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTaskQueryIface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TaskQueryIface {
+	mock := &TaskQueryIface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
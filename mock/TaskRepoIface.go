@@ -7,6 +7,8 @@ import (
 
 	models "github.com/UnknownOlympus/hephaestus/internal/models"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // TaskRepoIface is an autogenerated mock type for the TaskRepoIface type
@@ -14,6 +16,87 @@ type TaskRepoIface struct {
 	mock.Mock
 }
 
+// AnalyzeTables provides a mock function with given fields: ctx, tables
+func (_m *TaskRepoIface) AnalyzeTables(ctx context.Context, tables ...string) error {
+	_va := make([]interface{}, len(tables))
+	for _i := range tables {
+		_va[_i] = tables[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AnalyzeTables")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) error); ok {
+		r0 = rf(ctx, tables...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BackfillTaskContentHashes provides a mock function with given fields: ctx, batchSize
+func (_m *TaskRepoIface) BackfillTaskContentHashes(ctx context.Context, batchSize int) (int, error) {
+	ret := _m.Called(ctx, batchSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BackfillTaskContentHashes")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, batchSize)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, batchSize)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, batchSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTasksOlderThan provides a mock function with given fields: ctx, cutoff, batchSize
+func (_m *TaskRepoIface) DeleteTasksOlderThan(ctx context.Context, cutoff time.Time, batchSize int) (int64, error) {
+	ret := _m.Called(ctx, cutoff, batchSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTasksOlderThan")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) (int64, error)); ok {
+		return rf(ctx, cutoff, batchSize)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) int64); ok {
+		r0 = rf(ctx, cutoff, batchSize)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, cutoff, batchSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetOrCreateTaskTypeID provides a mock function with given fields: ctx, typeName
 func (_m *TaskRepoIface) GetOrCreateTaskTypeID(ctx context.Context, typeName string) (int, error) {
 	ret := _m.Called(ctx, typeName)
@@ -42,6 +125,36 @@ func (_m *TaskRepoIface) GetOrCreateTaskTypeID(ctx context.Context, typeName str
 	return r0, r1
 }
 
+// GetTaskClosedStates provides a mock function with given fields: ctx, taskIDs
+func (_m *TaskRepoIface) GetTaskClosedStates(ctx context.Context, taskIDs []int) (map[int]bool, error) {
+	ret := _m.Called(ctx, taskIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskClosedStates")
+	}
+
+	var r0 map[int]bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) (map[int]bool, error)); ok {
+		return rf(ctx, taskIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) map[int]bool); ok {
+		r0 = rf(ctx, taskIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]bool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, taskIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveTaskData provides a mock function with given fields: ctx, task
 func (_m *TaskRepoIface) SaveTaskData(ctx context.Context, task models.Task) error {
 	ret := _m.Called(ctx, task)
@@ -60,6 +173,24 @@ func (_m *TaskRepoIface) SaveTaskData(ctx context.Context, task models.Task) err
 	return r0
 }
 
+// UpdateTaskComments provides a mock function with given fields: ctx, taskID, comments
+func (_m *TaskRepoIface) UpdateTaskComments(ctx context.Context, taskID int, comments []string) error {
+	ret := _m.Called(ctx, taskID, comments)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTaskComments")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []string) error); ok {
+		r0 = rf(ctx, taskID, comments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateTaskExecutors provides a mock function with given fields: ctx, taskID, executors
 func (_m *TaskRepoIface) UpdateTaskExecutors(ctx context.Context, taskID int, executors []string) error {
 	ret := _m.Called(ctx, taskID, executors)